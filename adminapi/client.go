@@ -0,0 +1,121 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package adminapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/approval"
+	"github.com/ChainSafe/chainbridge-celo/deadletter"
+	"github.com/ChainSafe/chainbridge-celo/limiter"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+)
+
+// Client talks to admin API of a running relayer
+type Client struct {
+	url   string
+	token string
+	http  *http.Client
+}
+
+func NewClient(url string) *Client {
+	return &Client{
+		url:  strings.TrimSuffix(url, "/"),
+		http: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SetToken makes client send token as bearer token with every request
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+// TrippedResources returns resources which circuit breakers are tripped on all chains
+func (c *Client) TrippedResources() ([]*limiter.TrippedResource, error) {
+	res := make([]*limiter.TrippedResource, 0)
+	err := c.do(http.MethodGet, trippedPath, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Release releases tripped circuit breaker of resource rId on chain chainID
+func (c *Client) Release(chainID utils.ChainId, rId utils.ResourceId) error {
+	return c.do(http.MethodPost, releasePath, &ReleaseRequest{ChainID: chainID, ResourceID: rId}, nil)
+}
+
+// Transfers returns transfers in approval queues of all chains
+func (c *Client) Transfers() ([]*approval.Transfer, error) {
+	res := make([]*approval.Transfer, 0)
+	err := c.do(http.MethodGet, approvalsPath, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Approve approves pending transfer from source with nonce parked on chain chainID
+func (c *Client) Approve(chainID, source utils.ChainId, nonce utils.Nonce) error {
+	return c.do(http.MethodPost, approvePath, &DecisionRequest{ChainID: chainID, Source: source, DepositNonce: nonce}, nil)
+}
+
+// Reject rejects pending transfer from source with nonce parked on chain chainID
+func (c *Client) Reject(chainID, source utils.ChainId, nonce utils.Nonce, reason string) error {
+	return c.do(http.MethodPost, rejectPath, &DecisionRequest{ChainID: chainID, Source: source, DepositNonce: nonce, Reason: reason}, nil)
+}
+
+// DeadLetters returns dead letters of all source chains
+func (c *Client) DeadLetters() ([]*deadletter.DeadLetter, error) {
+	res := make([]*deadletter.DeadLetter, 0)
+	err := c.do(http.MethodGet, deadLettersPath, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Retry routes again deposit with nonce made on chain chainID to dest
+func (c *Client) Retry(chainID, dest utils.ChainId, nonce utils.Nonce) error {
+	return c.do(http.MethodPost, retryPath, &RetryRequest{ChainID: chainID, Destination: dest, DepositNonce: nonce}, nil)
+}
+
+func (c *Client) do(method, path string, body interface{}, out interface{}) error {
+	buf := &bytes.Buffer{}
+	if body != nil {
+		err := json.NewEncoder(buf).Encode(body)
+		if err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, c.url+path, buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		e := &errorResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(e); err != nil || e.Error == "" {
+			return fmt.Errorf("admin API responded with status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("admin API: %s", e.Error)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
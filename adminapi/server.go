@@ -0,0 +1,346 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package adminapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/approval"
+	"github.com/ChainSafe/chainbridge-celo/deadletter"
+	"github.com/ChainSafe/chainbridge-celo/limiter"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	trippedPath     = "/limits/tripped"
+	releasePath     = "/limits/release"
+	approvalsPath   = "/approvals"
+	approvePath     = "/approvals/approve"
+	rejectPath      = "/approvals/reject"
+	deadLettersPath = "/deadletters"
+	retryPath       = "/deadletters/retry"
+)
+
+// Breaker is a per chain circuit breaker controlled by an operator
+type Breaker interface {
+	ChainID() utils.ChainId
+	Tripped() []*limiter.TrippedResource
+	Release(rId utils.ResourceId) error
+}
+
+// Approvals is a per chain queue of transfers waiting for operator decision
+type Approvals interface {
+	ChainID() utils.ChainId
+	Transfers() ([]*approval.Transfer, error)
+	Approve(source utils.ChainId, nonce utils.Nonce) error
+	Reject(source utils.ChainId, nonce utils.Nonce, reason string) error
+}
+
+// DeadLetters is a per source chain store of deposits that could not be routed
+type DeadLetters interface {
+	ChainID() utils.ChainId
+	DeadLetters() ([]*deadletter.DeadLetter, error)
+	Retry(dest utils.ChainId, nonce utils.Nonce) error
+}
+
+type ReleaseRequest struct {
+	ChainID    utils.ChainId    `json:"chainId"`
+	ResourceID utils.ResourceId `json:"resourceId"`
+}
+
+// DecisionRequest identifies transfer in approval queue of ChainID by its source and nonce
+type DecisionRequest struct {
+	ChainID      utils.ChainId `json:"chainId"`
+	Source       utils.ChainId `json:"source"`
+	DepositNonce utils.Nonce   `json:"depositNonce"`
+	Reason       string        `json:"reason,omitempty"`
+}
+
+// RetryRequest identifies dead letter of deposit made on ChainID to Destination
+type RetryRequest struct {
+	ChainID      utils.ChainId `json:"chainId"`
+	Destination  utils.ChainId `json:"destination"`
+	DepositNonce utils.Nonce   `json:"depositNonce"`
+}
+
+var ErrNotLoopback = errors.New("admin API address is not loopback, token should be set to expose it")
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server is a local HTTP API that lets operator interact with running relayer
+type Server struct {
+	addr        string
+	token       string // Bearer token requests should carry, requests are not authenticated if empty
+	mux         *http.ServeMux
+	breakers    map[utils.ChainId]Breaker
+	approvals   map[utils.ChainId]Approvals
+	deadLetters map[utils.ChainId]DeadLetters
+	lock        sync.RWMutex
+}
+
+func NewServer(addr string) *Server {
+	s := &Server{
+		addr:        addr,
+		mux:         http.NewServeMux(),
+		breakers:    make(map[utils.ChainId]Breaker),
+		approvals:   make(map[utils.ChainId]Approvals),
+		deadLetters: make(map[utils.ChainId]DeadLetters),
+	}
+	s.mux.HandleFunc(trippedPath, s.handleTripped)
+	s.mux.HandleFunc(releasePath, s.handleRelease)
+	s.mux.HandleFunc(approvalsPath, s.handleApprovals)
+	s.mux.HandleFunc(approvePath, s.handleDecision)
+	s.mux.HandleFunc(rejectPath, s.handleDecision)
+	s.mux.HandleFunc(deadLettersPath, s.handleDeadLetters)
+	s.mux.HandleFunc(retryPath, s.handleRetry)
+	return s
+}
+
+func (s *Server) RegisterBreaker(b Breaker) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.breakers[b.ChainID()] = b
+}
+
+func (s *Server) RegisterApprovals(a Approvals) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.approvals[a.ChainID()] = a
+}
+
+func (s *Server) RegisterDeadLetters(d DeadLetters) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.deadLetters[d.ChainID()] = d
+}
+
+// SetToken makes server accept only requests carrying token as bearer token
+func (s *Server) SetToken(token string) {
+	s.token = token
+}
+
+// CheckAddr returns ErrNotLoopback if server would listen on non loopback address without token
+func (s *Server) CheckAddr() error {
+	if s.token != "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(s.addr)
+	if err != nil {
+		return fmt.Errorf("invalid admin API address %s: %w", s.addr, err)
+	}
+	if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrNotLoopback, s.addr)
+}
+
+// handler returns API handler that rejects requests without server token if it is set
+func (s *Server) handler() http.Handler {
+	if s.token == "" {
+		return s.mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+			return
+		}
+		s.mux.ServeHTTP(w, r)
+	})
+}
+
+// Start serves API in background until stop channel is closed
+func (s *Server) Start(stop <-chan struct{}, sysErr chan<- error) {
+	srv := &http.Server{Addr: s.addr, Handler: s.handler()}
+	go func() {
+		log.Info().Str("addr", s.addr).Msg("Starting admin API")
+		err := srv.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			sysErr <- fmt.Errorf("admin API failed: %w", err)
+		}
+	}()
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}()
+}
+
+func (s *Server) handleTripped(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	res := make([]*limiter.TrippedResource, 0)
+	for _, b := range s.breakers {
+		res = append(res, b.Tripped()...)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) handleRelease(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	req := &ReleaseRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.lock.RLock()
+	b, ok := s.breakers[req.ChainID]
+	s.lock.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no volume limits for chain %d", req.ChainID))
+		return
+	}
+	err = b.Release(req.ResourceID)
+	if err != nil {
+		if errors.Is(err, limiter.ErrNotTripped) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleApprovals(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	res := make([]*approval.Transfer, 0)
+	for _, a := range s.approvals {
+		transfers, err := a.Transfers()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		res = append(res, transfers...)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) handleDecision(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	req := &DecisionRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.lock.RLock()
+	a, ok := s.approvals[req.ChainID]
+	s.lock.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no approval queue for chain %d", req.ChainID))
+		return
+	}
+	if r.URL.Path == approvePath {
+		err = a.Approve(req.Source, req.DepositNonce)
+	} else {
+		if req.Reason == "" {
+			writeError(w, http.StatusBadRequest, errors.New("reject reason is required"))
+			return
+		}
+		err = a.Reject(req.Source, req.DepositNonce, req.Reason)
+	}
+	if err != nil {
+		if errors.Is(err, approval.ErrTransferNotFound) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, approval.ErrTransferNotPending) {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	res := make([]*deadletter.DeadLetter, 0)
+	for _, d := range s.deadLetters {
+		dls, err := d.DeadLetters()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		res = append(res, dls...)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) handleRetry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	req := &RetryRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.lock.RLock()
+	d, ok := s.deadLetters[req.ChainID]
+	s.lock.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no dead letters for chain %d", req.ChainID))
+		return
+	}
+	err = d.Retry(req.Destination, req.DepositNonce)
+	if err != nil {
+		if errors.Is(err, deadletter.ErrDeadLetterNotFound) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write admin API response")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &errorResponse{Error: err.Error()})
+}
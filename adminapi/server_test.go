@@ -0,0 +1,212 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package adminapi
+
+import (
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/approval"
+	"github.com/ChainSafe/chainbridge-celo/deadletter"
+	"github.com/ChainSafe/chainbridge-celo/limiter"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type testBreaker struct {
+	tripped map[utils.ResourceId]*limiter.TrippedResource
+}
+
+func (b *testBreaker) ChainID() utils.ChainId {
+	return 1
+}
+
+func (b *testBreaker) Tripped() []*limiter.TrippedResource {
+	res := make([]*limiter.TrippedResource, 0)
+	for _, t := range b.tripped {
+		res = append(res, t)
+	}
+	return res
+}
+
+func (b *testBreaker) Release(rId utils.ResourceId) error {
+	if _, ok := b.tripped[rId]; !ok {
+		return limiter.ErrNotTripped
+	}
+	delete(b.tripped, rId)
+	return nil
+}
+
+type testApprovals struct {
+	transfers map[utils.Nonce]*approval.Transfer
+}
+
+func (a *testApprovals) ChainID() utils.ChainId {
+	return 2
+}
+
+func (a *testApprovals) Transfers() ([]*approval.Transfer, error) {
+	res := make([]*approval.Transfer, 0)
+	for _, t := range a.transfers {
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+func (a *testApprovals) Approve(source utils.ChainId, nonce utils.Nonce) error {
+	if _, ok := a.transfers[nonce]; !ok {
+		return approval.ErrTransferNotFound
+	}
+	delete(a.transfers, nonce)
+	return nil
+}
+
+func (a *testApprovals) Reject(source utils.ChainId, nonce utils.Nonce, reason string) error {
+	t, ok := a.transfers[nonce]
+	if !ok {
+		return approval.ErrTransferNotFound
+	}
+	t.Status = approval.StatusRejected
+	t.Reason = reason
+	return nil
+}
+
+type testDeadLetters struct {
+	deadLetters map[utils.Nonce]*deadletter.DeadLetter
+}
+
+func (d *testDeadLetters) ChainID() utils.ChainId {
+	return 1
+}
+
+func (d *testDeadLetters) DeadLetters() ([]*deadletter.DeadLetter, error) {
+	res := make([]*deadletter.DeadLetter, 0)
+	for _, dl := range d.deadLetters {
+		res = append(res, dl)
+	}
+	return res, nil
+}
+
+func (d *testDeadLetters) Retry(dest utils.ChainId, nonce utils.Nonce) error {
+	if _, ok := d.deadLetters[nonce]; !ok {
+		return deadletter.ErrDeadLetterNotFound
+	}
+	delete(d.deadLetters, nonce)
+	return nil
+}
+
+type ServerTestSuite struct {
+	suite.Suite
+	breaker     *testBreaker
+	approvals   *testApprovals
+	deadLetters *testDeadLetters
+	server      *httptest.Server
+	client      *Client
+}
+
+func TestRunServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+func (s *ServerTestSuite) SetupSuite()    {}
+func (s *ServerTestSuite) TearDownSuite() {}
+func (s *ServerTestSuite) SetupTest() {
+	s.breaker = &testBreaker{tripped: map[utils.ResourceId]*limiter.TrippedResource{
+		{1}: {ChainID: 1, ResourceID: utils.ResourceId{1}, Reason: "limit", TrippedAt: time.Now()},
+	}}
+	s.approvals = &testApprovals{transfers: map[utils.Nonce]*approval.Transfer{
+		1: {Message: utils.NewFungibleTransfer(1, 2, 1, utils.ResourceId{1}, nil, nil, big.NewInt(1000), []byte{1}), Amount: big.NewInt(1000), Status: approval.StatusPending},
+		2: {Message: utils.NewFungibleTransfer(1, 2, 2, utils.ResourceId{1}, nil, nil, big.NewInt(1000), []byte{1}), Amount: big.NewInt(1000), Status: approval.StatusPending},
+	}}
+	s.deadLetters = &testDeadLetters{deadLetters: map[utils.Nonce]*deadletter.DeadLetter{
+		3: {Source: 1, Destination: 2, Nonce: 3, Reason: "unrecognized handler"},
+	}}
+	srv := NewServer("")
+	srv.RegisterBreaker(s.breaker)
+	srv.RegisterApprovals(s.approvals)
+	srv.RegisterDeadLetters(s.deadLetters)
+	s.server = httptest.NewServer(srv.handler())
+	s.client = NewClient(s.server.URL)
+}
+func (s *ServerTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ServerTestSuite) TestListAndRelease() {
+	tripped, err := s.client.TrippedResources()
+	s.Nil(err)
+	s.Equal(1, len(tripped))
+	s.Equal(utils.ResourceId{1}, tripped[0].ResourceID)
+
+	s.Nil(s.client.Release(1, utils.ResourceId{1}))
+	tripped, err = s.client.TrippedResources()
+	s.Nil(err)
+	s.Equal(0, len(tripped))
+}
+
+func (s *ServerTestSuite) TestReleaseErrors() {
+	err := s.client.Release(1, utils.ResourceId{2})
+	s.NotNil(err)
+	s.Contains(err.Error(), limiter.ErrNotTripped.Error())
+
+	err = s.client.Release(5, utils.ResourceId{1})
+	s.NotNil(err)
+}
+
+func (s *ServerTestSuite) TestApprovals() {
+	transfers, err := s.client.Transfers()
+	s.Nil(err)
+	s.Equal(2, len(transfers))
+
+	s.Nil(s.client.Approve(2, 1, 1))
+	s.NotNil(s.client.Reject(2, 1, 2, ""))
+	s.Nil(s.client.Reject(2, 1, 2, "suspicious"))
+	transfers, err = s.client.Transfers()
+	s.Nil(err)
+	s.Equal(1, len(transfers))
+	s.Equal("suspicious", transfers[0].Reason)
+
+	err = s.client.Approve(2, 1, 1)
+	s.NotNil(err)
+	s.Contains(err.Error(), approval.ErrTransferNotFound.Error())
+}
+
+func (s *ServerTestSuite) TestDeadLetters() {
+	dls, err := s.client.DeadLetters()
+	s.Nil(err)
+	s.Equal(1, len(dls))
+	s.Equal("unrecognized handler", dls[0].Reason)
+
+	s.Nil(s.client.Retry(1, 2, 3))
+	dls, err = s.client.DeadLetters()
+	s.Nil(err)
+	s.Equal(0, len(dls))
+
+	err = s.client.Retry(1, 2, 3)
+	s.NotNil(err)
+	s.Contains(err.Error(), deadletter.ErrDeadLetterNotFound.Error())
+	s.NotNil(s.client.Retry(4, 2, 3))
+}
+
+func (s *ServerTestSuite) TestToken() {
+	srv := NewServer("0.0.0.0:8002")
+	s.True(errors.Is(srv.CheckAddr(), ErrNotLoopback))
+	s.Nil(NewServer("127.0.0.1:8002").CheckAddr())
+	s.Nil(NewServer("localhost:8002").CheckAddr())
+
+	srv.SetToken("secret")
+	s.Nil(srv.CheckAddr())
+	srv.RegisterApprovals(s.approvals)
+	server := httptest.NewServer(srv.handler())
+	defer server.Close()
+	c := NewClient(server.URL)
+	s.NotNil(c.Approve(2, 1, 1))
+	s.Contains(s.approvals.transfers, utils.Nonce(1))
+	c.SetToken("secret")
+	s.Nil(c.Approve(2, 1, 1))
+	s.NotContains(s.approvals.transfers, utils.Nonce(1))
+}
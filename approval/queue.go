@@ -0,0 +1,279 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package approval
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/rs/zerolog/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	transferKeyPrefix = "approvalTransfer"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusFailed   = "failed"
+	StatusRejected = "rejected"
+)
+
+var ErrTransferNotFound = errors.New("transfer not found in approval queue")
+var ErrTransferNotPending = errors.New("transfer is not pending approval")
+
+// Transfer is a message parked in approval queue
+type Transfer struct {
+	Message  *utils.Message `json:"message"`
+	Amount   *big.Int       `json:"amount"`
+	Status   string         `json:"status"`
+	Reason   string         `json:"reason,omitempty"`
+	ParkedAt time.Time      `json:"parkedAt"`
+}
+
+// Queue is a persistent queue of transfers destined to a single chain that exceed approval threshold of its resource
+// and should not be voted until an operator approves them.
+type Queue struct {
+	db         *leveldb.DB
+	chainID    utils.ChainId
+	thresholds map[utils.ResourceId]*big.Int
+	resolve    func(m *utils.Message) bool
+	resolving  map[string]struct{}
+	lock       sync.Mutex
+}
+
+func NewQueue(db *leveldb.DB, chainID utils.ChainId, thresholds map[utils.ResourceId]*big.Int) *Queue {
+	return &Queue{
+		db:         db,
+		chainID:    chainID,
+		thresholds: thresholds,
+		resolving:  make(map[string]struct{}),
+	}
+}
+
+// SetResolver sets function that continues processing of approved messages
+func (q *Queue) SetResolver(resolve func(m *utils.Message) bool) {
+	q.resolve = resolve
+}
+
+func (q *Queue) ChainID() utils.ChainId {
+	return q.chainID
+}
+
+// RequiresApproval returns true if m is a fungible transfer with amount above threshold of its resource
+func (q *Queue) RequiresApproval(m *utils.Message) bool {
+	threshold, ok := q.thresholds[m.ResourceId]
+	if !ok || m.Type != utils.FungibleTransfer {
+		return false
+	}
+	return messageAmount(m).Cmp(threshold) == 1
+}
+
+// Park persists m as pending approval. Parking already parked message is a no-op
+func (q *Queue) Park(m *utils.Message) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	existing, err := q.get(m.Source, m.DepositNonce)
+	if err == nil {
+		log.Info().Interface("src", m.Source).Interface("nonce", m.DepositNonce).Str("status", existing.Status).Msg("Transfer already in approval queue")
+		return nil
+	}
+	if !errors.Is(err, ErrTransferNotFound) {
+		return err
+	}
+	t := &Transfer{
+		Message:  m,
+		Amount:   messageAmount(m),
+		Status:   StatusPending,
+		ParkedAt: time.Now(),
+	}
+	err = q.put(t)
+	if err != nil {
+		return err
+	}
+	log.Warn().Interface("src", m.Source).Interface("dst", m.Destination).Interface("nonce", m.DepositNonce).Str("rId", m.ResourceId.Hex()).Str("amount", t.Amount.String()).Msg("Transfer exceeds approval threshold, parked for manual approval")
+	return nil
+}
+
+// Transfers returns all transfers in queue including rejected ones
+func (q *Queue) Transfers() ([]*Transfer, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	res := make([]*Transfer, 0)
+	iter := q.db.NewIterator(util.BytesPrefix(transferPrefix(q.chainID)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		t := &Transfer{}
+		err := json.Unmarshal(iter.Value(), t)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Approve marks transfer as approved and hands it to resolver. Transfer is removed from queue once it is resolved,
+// if resolving fails it is marked as failed and can be approved again. Transfer that stayed approved after restart
+// can be approved again as well
+func (q *Queue) Approve(source utils.ChainId, nonce utils.Nonce) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	t, err := q.get(source, nonce)
+	if err != nil {
+		return err
+	}
+	key := transferKey(q.chainID, source, nonce)
+	if _, ok := q.resolving[string(key)]; ok || (t.Status != StatusPending && t.Status != StatusFailed && t.Status != StatusApproved) {
+		return ErrTransferNotPending
+	}
+	if q.resolve == nil {
+		return errors.New("approval queue has no resolver")
+	}
+	t.Status = StatusApproved
+	t.Reason = ""
+	err = q.put(t)
+	if err != nil {
+		return err
+	}
+	q.resolving[string(key)] = struct{}{}
+	log.Info().Interface("src", source).Interface("nonce", nonce).Msg("Transfer approved by operator")
+	go q.resolveApproved(t)
+	return nil
+}
+
+// resolveApproved resolves approved transfer and removes it from queue, or marks it as failed if resolving fails
+func (q *Queue) resolveApproved(t *Transfer) {
+	ok := q.resolve(t.Message)
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	key := transferKey(q.chainID, t.Message.Source, t.Message.DepositNonce)
+	delete(q.resolving, string(key))
+	if ok {
+		err := q.db.Delete(key, nil)
+		if err != nil {
+			log.Error().Err(err).Interface("src", t.Message.Source).Interface("nonce", t.Message.DepositNonce).Msg("Failed to remove resolved transfer from approval queue")
+		}
+		return
+	}
+	t.Status = StatusFailed
+	t.Reason = "vote failed after approval"
+	err := q.put(t)
+	if err != nil {
+		log.Error().Err(err).Interface("src", t.Message.Source).Interface("nonce", t.Message.DepositNonce).Msg("Failed to mark transfer as failed in approval queue")
+		return
+	}
+	log.Error().Interface("src", t.Message.Source).Interface("nonce", t.Message.DepositNonce).Msg("Approved transfer was not voted, it can be approved again")
+}
+
+// Reject marks pending transfer as rejected, it stays in queue and will not be parked again
+func (q *Queue) Reject(source utils.ChainId, nonce utils.Nonce, reason string) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	t, err := q.get(source, nonce)
+	if err != nil {
+		return err
+	}
+	if t.Status != StatusPending {
+		return ErrTransferNotPending
+	}
+	t.Status = StatusRejected
+	t.Reason = reason
+	err = q.put(t)
+	if err != nil {
+		return err
+	}
+	log.Warn().Interface("src", source).Interface("nonce", nonce).Str("reason", reason).Msg("Transfer rejected by operator")
+	return nil
+}
+
+func (q *Queue) get(source utils.ChainId, nonce utils.Nonce) (*Transfer, error) {
+	data, err := q.db.Get(transferKey(q.chainID, source, nonce), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, ErrTransferNotFound
+		}
+		return nil, err
+	}
+	t := &Transfer{}
+	err = json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (q *Queue) put(t *Transfer) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return q.db.Put(transferKey(q.chainID, t.Message.Source, t.Message.DepositNonce), data, nil)
+}
+
+func messageAmount(m *utils.Message) *big.Int {
+	if len(m.Payload) == 0 {
+		return big.NewInt(0)
+	}
+	amount, ok := m.Payload[0].([]byte)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return new(big.Int).SetBytes(amount)
+}
+
+func transferPrefix(chainID utils.ChainId) []byte {
+	key := bytes.NewBufferString(transferKeyPrefix)
+	key.WriteByte(uint8(chainID))
+	return key.Bytes()
+}
+
+func transferKey(chainID utils.ChainId, source utils.ChainId, nonce utils.Nonce) []byte {
+	key := bytes.NewBuffer(transferPrefix(chainID))
+	key.WriteByte(uint8(source))
+	_ = binary.Write(key, binary.BigEndian, uint64(nonce))
+	return key.Bytes()
+}
+
+// ParseThresholds parses approval thresholds from comma separated list of `resourceID:amount` entries
+func ParseThresholds(raw string) (map[utils.ResourceId]*big.Int, error) {
+	thresholds := make(map[utils.ResourceId]*big.Int)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		fields := strings.Split(entry, ":")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed approval threshold %s, expected resourceID:amount", entry)
+		}
+		rIdBytes, err := hexutil.Decode(fields[0])
+		if err != nil || len(rIdBytes) != 32 {
+			return nil, fmt.Errorf("invalid resource id %s", fields[0])
+		}
+		amount, ok := new(big.Int).SetString(fields[1], 10)
+		if !ok {
+			return nil, fmt.Errorf("unable to parse approval threshold amount %s", fields[1])
+		}
+		var rId utils.ResourceId
+		copy(rId[:], rIdBytes)
+		thresholds[rId] = amount
+	}
+	return thresholds, nil
+}
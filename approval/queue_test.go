@@ -0,0 +1,161 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package approval
+
+import (
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/stretchr/testify/suite"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+type QueueTestSuite struct {
+	suite.Suite
+	db    *leveldb.DB
+	queue *Queue
+	rId   utils.ResourceId
+}
+
+func TestRunQueueTestSuite(t *testing.T) {
+	suite.Run(t, new(QueueTestSuite))
+}
+
+func (s *QueueTestSuite) SetupSuite()    {}
+func (s *QueueTestSuite) TearDownSuite() {}
+func (s *QueueTestSuite) SetupTest() {
+	db, err := leveldb.OpenFile("./test/db", nil)
+	if err != nil {
+		s.Fail(err.Error())
+	}
+	s.db = db
+	s.rId = utils.ResourceId{1}
+	s.queue = NewQueue(db, 2, map[utils.ResourceId]*big.Int{s.rId: big.NewInt(100)})
+}
+func (s *QueueTestSuite) TearDownTest() {
+	s.db.Close()
+	os.RemoveAll("./test")
+}
+
+func (s *QueueTestSuite) TestRequiresApproval() {
+	s.False(s.queue.RequiresApproval(utils.NewFungibleTransfer(1, 2, 1, s.rId, nil, nil, big.NewInt(100), []byte{1})))
+	s.True(s.queue.RequiresApproval(utils.NewFungibleTransfer(1, 2, 1, s.rId, nil, nil, big.NewInt(101), []byte{1})))
+	s.False(s.queue.RequiresApproval(utils.NewFungibleTransfer(1, 2, 1, utils.ResourceId{2}, nil, nil, big.NewInt(101), []byte{1})))
+	s.False(s.queue.RequiresApproval(utils.NewNonFungibleTransfer(1, 2, 1, s.rId, nil, nil, big.NewInt(101), []byte{1}, []byte{})))
+}
+
+func (s *QueueTestSuite) TestParkAndApprove() {
+	resolved := make(chan *utils.Message)
+	s.queue.SetResolver(func(m *utils.Message) bool { resolved <- m; return true })
+	m := utils.NewFungibleTransfer(1, 2, 5, s.rId, &utils.MerkleProof{}, &utils.SignatureVerification{}, big.NewInt(1000), []byte{1})
+	s.Nil(s.queue.Park(m))
+	// Parking twice is a no-op
+	s.Nil(s.queue.Park(m))
+
+	transfers, err := s.queue.Transfers()
+	s.Nil(err)
+	s.Equal(1, len(transfers))
+	s.Equal(StatusPending, transfers[0].Status)
+	s.Equal(big.NewInt(1000), transfers[0].Amount)
+
+	s.Nil(s.queue.Approve(1, 5))
+	select {
+	case r := <-resolved:
+		s.Equal(m, r)
+	case <-time.After(time.Second):
+		s.Fail("approved message was not resolved")
+	}
+	s.Eventually(func() bool {
+		transfers, err = s.queue.Transfers()
+		return err == nil && len(transfers) == 0
+	}, time.Second, 10*time.Millisecond)
+	s.Equal(ErrTransferNotFound, s.queue.Approve(1, 5))
+}
+
+func (s *QueueTestSuite) TestApproveFailedIsRetryable() {
+	resolved := make(chan *utils.Message)
+	release := make(chan bool)
+	s.queue.SetResolver(func(m *utils.Message) bool { resolved <- m; return <-release })
+	m := utils.NewFungibleTransfer(1, 2, 5, s.rId, &utils.MerkleProof{}, &utils.SignatureVerification{}, big.NewInt(1000), []byte{1})
+	s.Nil(s.queue.Park(m))
+
+	s.Nil(s.queue.Approve(1, 5))
+	<-resolved
+	// Transfer stays in queue while it is resolved and can not be approved twice
+	transfers, err := s.queue.Transfers()
+	s.Nil(err)
+	s.Equal(StatusApproved, transfers[0].Status)
+	s.Equal(ErrTransferNotPending, s.queue.Approve(1, 5))
+	release <- false
+	s.Eventually(func() bool {
+		transfers, err = s.queue.Transfers()
+		return err == nil && len(transfers) == 1 && transfers[0].Status == StatusFailed
+	}, time.Second, 10*time.Millisecond)
+
+	// Failed transfer is approved again and removed once vote succeeds
+	s.Nil(s.queue.Approve(1, 5))
+	<-resolved
+	release <- true
+	s.Eventually(func() bool {
+		transfers, err = s.queue.Transfers()
+		return err == nil && len(transfers) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *QueueTestSuite) TestApprovedTransferRetryableAfterRestart() {
+	m := utils.NewFungibleTransfer(1, 2, 5, s.rId, &utils.MerkleProof{}, &utils.SignatureVerification{}, big.NewInt(1000), []byte{1})
+	s.Nil(s.queue.Park(m))
+	t, err := s.queue.get(1, 5)
+	s.Nil(err)
+	t.Status = StatusApproved
+	s.Nil(s.queue.put(t))
+
+	restarted := NewQueue(s.db, 2, nil)
+	resolved := make(chan *utils.Message, 1)
+	restarted.SetResolver(func(m *utils.Message) bool { resolved <- m; return true })
+	s.Nil(restarted.Approve(1, 5))
+	select {
+	case r := <-resolved:
+		s.Equal(m.DepositNonce, r.DepositNonce)
+	case <-time.After(time.Second):
+		s.Fail("approved message was not resolved")
+	}
+	s.Eventually(func() bool {
+		transfers, err := restarted.Transfers()
+		return err == nil && len(transfers) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *QueueTestSuite) TestReject() {
+	s.queue.SetResolver(func(m *utils.Message) bool { s.Fail("rejected message should not be resolved"); return false })
+	m := utils.NewFungibleTransfer(1, 2, 5, s.rId, nil, nil, big.NewInt(1000), []byte{1})
+	s.Nil(s.queue.Park(m))
+	s.Nil(s.queue.Reject(1, 5, "suspicious"))
+
+	// Rejected transfer stays rejected after restart and can not be approved
+	restarted := NewQueue(s.db, 2, nil)
+	transfers, err := restarted.Transfers()
+	s.Nil(err)
+	s.Equal(1, len(transfers))
+	s.Equal(StatusRejected, transfers[0].Status)
+	s.Equal("suspicious", transfers[0].Reason)
+	s.Equal(ErrTransferNotPending, s.queue.Approve(1, 5))
+	s.Nil(s.queue.Park(m))
+	transfers, err = s.queue.Transfers()
+	s.Nil(err)
+	s.Equal(StatusRejected, transfers[0].Status)
+}
+
+func (s *QueueTestSuite) TestParseThresholds() {
+	thresholds, err := ParseThresholds("0x0100000000000000000000000000000000000000000000000000000000000000:1000")
+	s.Nil(err)
+	s.Equal(big.NewInt(1000), thresholds[s.rId])
+	_, err = ParseThresholds("0x0100000000000000000000000000000000000000000000000000000000000000")
+	s.NotNil(err)
+	_, err = ParseThresholds("0x0100000000000000000000000000000000000000000000000000000000000000:abc")
+	s.NotNil(err)
+}
@@ -8,12 +8,21 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
-func NewBlockStoreDB(relayerAddress string, blockstorePath string, chainID utils.ChainId, freshStart bool, startBlock *big.Int) (*Blockstore, error) {
-	bs, err := NewBlockstore(blockstorePath, chainID, relayerAddress)
+// NewBlockStoreDB returns blockstore of relayer LevelDB. Checkpoint of legacy block file in blockstorePath is migrated
+// on first start. Unless freshStart is set, startBlock is moved to stored checkpoint if it is later
+func NewBlockStoreDB(db *leveldb.DB, relayerAddress string, blockstorePath string, chainID utils.ChainId, freshStart bool, startBlock *big.Int) (*LevelDBBlockstore, error) {
+	bs := NewLevelDBBlockstore(db, chainID, relayerAddress)
+	file, err := NewBlockstore(blockstorePath, chainID, relayerAddress)
+	if err != nil {
+		return nil, err
+	}
+	_, err = bs.MigrateFile(file)
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +40,7 @@ func NewBlockStoreDB(relayerAddress string, blockstorePath string, chainID utils
 
 const PathPostfix = ".chainbridge/blockstore"
 
-// Blockstore implements Blockstorer.
+// Blockstore is legacy block checkpoint kept in text file. Relayer only reads it to migrate checkpoint to LevelDBBlockstore
 type Blockstore struct {
 	path     string // Path excluding filename
 	fullPath string
@@ -89,7 +98,10 @@ func (b *Blockstore) TryLoadLatestBlock() (*big.Int, error) {
 		if err != nil {
 			return nil, err
 		}
-		block, _ := big.NewInt(0).SetString(string(dat), 10)
+		block, ok := big.NewInt(0).SetString(strings.TrimSpace(string(dat)), 10)
+		if !ok {
+			return nil, fmt.Errorf("malformed block %q in %s", dat, b.fullPath)
+		}
 		return block, nil
 	}
 	// Otherwise just return 0
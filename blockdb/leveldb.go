@@ -0,0 +1,166 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+package blockdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Blockstore key layout in relayer LevelDB. Relayer is address of relayer key or observer blockstore name, block
+// numbers are 8 bytes big endian.
+//
+//	blockstoreCheckpoint | chainID | relayer         -> latest processed block
+//	blockstoreHash       | chainID | relayer | block -> hash of processed block
+const (
+	checkpointKeyPrefix = "blockstoreCheckpoint"
+	hashKeyPrefix       = "blockstoreHash"
+)
+
+// Number of latest processed block hashes kept with checkpoint
+var HistorySize uint64 = 256
+
+var ErrBlockNotInHistory = errors.New("block is not in processed blocks history")
+
+// LevelDBBlockstore keeps block checkpoint of chain listener in LevelDB together with hashes of latest processed blocks
+type LevelDBBlockstore struct {
+	db      *leveldb.DB
+	chain   utils.ChainId
+	relayer string
+}
+
+func NewLevelDBBlockstore(db *leveldb.DB, chain utils.ChainId, relayer string) *LevelDBBlockstore {
+	return &LevelDBBlockstore{db: db, chain: chain, relayer: relayer}
+}
+
+// StoreBlock atomically sets block as checkpoint, stores its hash and removes hashes older than HistorySize blocks
+func (b *LevelDBBlockstore) StoreBlock(block *big.Int, hash common.Hash) error {
+	batch := new(leveldb.Batch)
+	batch.Put(b.checkpointKey(), encodeBlock(block))
+	batch.Put(b.hashKey(block), hash.Bytes())
+	if block.Uint64() >= HistorySize {
+		iter := b.db.NewIterator(&util.Range{Start: b.hashKey(big.NewInt(0)), Limit: b.hashKey(new(big.Int).SetUint64(block.Uint64() - HistorySize + 1))}, nil)
+		for iter.Next() {
+			batch.Delete(append([]byte{}, iter.Key()...))
+		}
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			return err
+		}
+	}
+	return b.db.Write(batch, nil)
+}
+
+// SetCheckpoint atomically sets checkpoint to block and removes history of later blocks, so they are processed again
+func (b *LevelDBBlockstore) SetCheckpoint(block *big.Int) error {
+	batch := new(leveldb.Batch)
+	batch.Put(b.checkpointKey(), encodeBlock(block))
+	iter := b.db.NewIterator(&util.Range{Start: b.hashKey(new(big.Int).Add(block, big.NewInt(1))), Limit: util.BytesPrefix(b.hashKeyPrefix()).Limit}, nil)
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return b.db.Write(batch, nil)
+}
+
+// TryLoadLatestBlock returns checkpoint block, 0 if checkpoint is not stored
+func (b *LevelDBBlockstore) TryLoadLatestBlock() (*big.Int, error) {
+	data, err := b.db.Get(b.checkpointKey(), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return big.NewInt(0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// BlockHash returns stored hash of processed block
+func (b *LevelDBBlockstore) BlockHash(block *big.Int) (common.Hash, error) {
+	data, err := b.db.Get(b.hashKey(block), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return common.Hash{}, ErrBlockNotInHistory
+	}
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(data), nil
+}
+
+// Checkpoint is stored block checkpoint of relayer
+type Checkpoint struct {
+	Relayer string
+	Block   *big.Int
+}
+
+// Checkpoints returns checkpoints of every relayer stored for chain
+func Checkpoints(db *leveldb.DB, chain utils.ChainId) ([]*Checkpoint, error) {
+	prefix := append([]byte(checkpointKeyPrefix), uint8(chain))
+	checkpoints := make([]*Checkpoint, 0)
+	iter := db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		checkpoints = append(checkpoints, &Checkpoint{
+			Relayer: string(iter.Key()[len(prefix):]),
+			Block:   new(big.Int).SetBytes(iter.Value()),
+		})
+	}
+	return checkpoints, iter.Error()
+}
+
+// MigrateFile moves checkpoint of legacy block file to LevelDB unless checkpoint is already stored there.
+// Migrated file is renamed so it is not loaded again. Returns true if checkpoint was migrated
+func (b *LevelDBBlockstore) MigrateFile(file *Blockstore) (bool, error) {
+	has, err := b.db.Has(b.checkpointKey(), nil)
+	if err != nil || has {
+		return false, err
+	}
+	exists, err := fileExists(file.fullPath)
+	if err != nil || !exists {
+		return false, err
+	}
+	block, err := file.TryLoadLatestBlock()
+	if err != nil {
+		return false, fmt.Errorf("unable to migrate blockstore file %s: %w", file.fullPath, err)
+	}
+	err = b.db.Put(b.checkpointKey(), encodeBlock(block), nil)
+	if err != nil {
+		return false, err
+	}
+	err = os.Rename(file.fullPath, file.fullPath+".migrated")
+	if err != nil {
+		return false, err
+	}
+	log.Info().Str("file", file.fullPath).Str("block", block.String()).Msg("Migrated blockstore file to LevelDB")
+	return true, nil
+}
+
+func (b *LevelDBBlockstore) checkpointKey() []byte {
+	return append(append([]byte(checkpointKeyPrefix), uint8(b.chain)), b.relayer...)
+}
+
+func (b *LevelDBBlockstore) hashKeyPrefix() []byte {
+	return append(append([]byte(hashKeyPrefix), uint8(b.chain)), b.relayer...)
+}
+
+func (b *LevelDBBlockstore) hashKey(block *big.Int) []byte {
+	return append(b.hashKeyPrefix(), encodeBlock(block)...)
+}
+
+func encodeBlock(block *big.Int) []byte {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, block.Uint64())
+	return data
+}
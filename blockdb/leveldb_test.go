@@ -0,0 +1,144 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+package blockdb
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/suite"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const testRelayer = "0xff93B45308FD417dF303D6515aB04D9e89a750Ca"
+
+type BlockstoreTestSuite struct {
+	suite.Suite
+	db *leveldb.DB
+}
+
+func TestRunBlockstoreTestSuite(t *testing.T) {
+	suite.Run(t, new(BlockstoreTestSuite))
+}
+func (s *BlockstoreTestSuite) SetupSuite()    {}
+func (s *BlockstoreTestSuite) TearDownSuite() {}
+func (s *BlockstoreTestSuite) SetupTest() {
+	db, err := leveldb.OpenFile("./test/db", nil)
+	if err != nil {
+		s.Fail(err.Error())
+	}
+	s.db = db
+}
+func (s *BlockstoreTestSuite) TearDownTest() {
+	s.db.Close()
+	os.RemoveAll("./test")
+}
+
+func (s *BlockstoreTestSuite) TestStoreBlock() {
+	bs := NewLevelDBBlockstore(s.db, 1, testRelayer)
+	latest, err := bs.TryLoadLatestBlock()
+	s.Nil(err)
+	s.Equal(0, latest.Cmp(big.NewInt(0)))
+
+	s.Nil(bs.StoreBlock(big.NewInt(10), common.Hash{0x0a}))
+	latest, err = bs.TryLoadLatestBlock()
+	s.Nil(err)
+	s.Equal(0, latest.Cmp(big.NewInt(10)))
+	hash, err := bs.BlockHash(big.NewInt(10))
+	s.Nil(err)
+	s.Equal(common.Hash{0x0a}, hash)
+
+	// Other chains and relayers have their own checkpoints
+	latest, err = NewLevelDBBlockstore(s.db, 2, testRelayer).TryLoadLatestBlock()
+	s.Nil(err)
+	s.Equal(0, latest.Cmp(big.NewInt(0)))
+	latest, err = NewLevelDBBlockstore(s.db, 1, "observer").TryLoadLatestBlock()
+	s.Nil(err)
+	s.Equal(0, latest.Cmp(big.NewInt(0)))
+}
+
+func (s *BlockstoreTestSuite) TestHistoryKeepsLatestBlocks() {
+	defer func(size uint64) { HistorySize = size }(HistorySize)
+	HistorySize = 3
+	bs := NewLevelDBBlockstore(s.db, 1, testRelayer)
+	for i := int64(1); i <= 5; i++ {
+		s.Nil(bs.StoreBlock(big.NewInt(i), common.BigToHash(big.NewInt(i))))
+	}
+
+	for i := int64(3); i <= 5; i++ {
+		hash, err := bs.BlockHash(big.NewInt(i))
+		s.Nil(err)
+		s.Equal(common.BigToHash(big.NewInt(i)), hash)
+	}
+	_, err := bs.BlockHash(big.NewInt(2))
+	s.True(errors.Is(err, ErrBlockNotInHistory))
+}
+
+func (s *BlockstoreTestSuite) TestMigrateFile() {
+	path := "./test/blockstore"
+	file, err := NewBlockstore(path, 1, testRelayer)
+	s.Nil(err)
+	s.Nil(file.StoreBlock(big.NewInt(420)))
+
+	startBlock := big.NewInt(100)
+	bs, err := NewBlockStoreDB(s.db, testRelayer, path, 1, false, startBlock)
+	s.Nil(err)
+	s.Equal(0, startBlock.Cmp(big.NewInt(420)))
+	latest, err := bs.TryLoadLatestBlock()
+	s.Nil(err)
+	s.Equal(0, latest.Cmp(big.NewInt(420)))
+	exists, err := fileExists(file.fullPath)
+	s.Nil(err)
+	s.False(exists)
+
+	// Checkpoint stored in LevelDB wins over file created later
+	s.Nil(file.StoreBlock(big.NewInt(1)))
+	migrated, err := bs.MigrateFile(file)
+	s.Nil(err)
+	s.False(migrated)
+}
+
+func (s *BlockstoreTestSuite) TestMigrateMalformedFile() {
+	path := "./test/blockstore"
+	s.Nil(os.MkdirAll(path, 0700))
+	s.Nil(ioutil.WriteFile(filepath.Join(path, getFileName(1, testRelayer)), []byte{}, 0600))
+
+	_, err := NewBlockStoreDB(s.db, testRelayer, path, 1, false, big.NewInt(0))
+	s.NotNil(err)
+}
+
+func (s *BlockstoreTestSuite) TestSetCheckpointDropsLaterHistory() {
+	bs := NewLevelDBBlockstore(s.db, 1, testRelayer)
+	for i := int64(1); i <= 5; i++ {
+		s.Nil(bs.StoreBlock(big.NewInt(i), common.BigToHash(big.NewInt(i))))
+	}
+	s.Nil(bs.SetCheckpoint(big.NewInt(3)))
+
+	latest, err := bs.TryLoadLatestBlock()
+	s.Nil(err)
+	s.Equal(0, latest.Cmp(big.NewInt(3)))
+	hash, err := bs.BlockHash(big.NewInt(3))
+	s.Nil(err)
+	s.Equal(common.BigToHash(big.NewInt(3)), hash)
+	_, err = bs.BlockHash(big.NewInt(4))
+	s.True(errors.Is(err, ErrBlockNotInHistory))
+}
+
+func (s *BlockstoreTestSuite) TestCheckpoints() {
+	s.Nil(NewLevelDBBlockstore(s.db, 1, testRelayer).StoreBlock(big.NewInt(10), common.Hash{}))
+	s.Nil(NewLevelDBBlockstore(s.db, 1, "observer").StoreBlock(big.NewInt(20), common.Hash{}))
+	s.Nil(NewLevelDBBlockstore(s.db, 2, testRelayer).StoreBlock(big.NewInt(30), common.Hash{}))
+
+	checkpoints, err := Checkpoints(s.db, 1)
+	s.Nil(err)
+	s.Len(checkpoints, 2)
+	s.Equal(testRelayer, checkpoints[0].Relayer)
+	s.Equal(0, checkpoints[0].Block.Cmp(big.NewInt(10)))
+	s.Equal("observer", checkpoints[1].Relayer)
+	s.Equal(0, checkpoints[1].Block.Cmp(big.NewInt(20)))
+}
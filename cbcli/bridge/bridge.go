@@ -11,5 +11,7 @@ var BridgeCLICMDS = &cli.Command{
 		cancelProposalCMD,
 		queryProposalCMD,
 		queryResourceCMD,
+		verifyProofCMD,
+		proposalHashCMD,
 	},
 }
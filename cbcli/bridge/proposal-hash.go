@@ -0,0 +1,239 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	bridgeHandler "github.com/ChainSafe/chainbridge-celo/bindings/Bridge"
+	erc20Handler "github.com/ChainSafe/chainbridge-celo/bindings/ERC20Handler"
+	erc721Handler "github.com/ChainSafe/chainbridge-celo/bindings/ERC721Handler"
+	"github.com/ChainSafe/chainbridge-celo/bindings/GenericHandler"
+	"github.com/ChainSafe/chainbridge-celo/cbcli/cliutils"
+	"github.com/ChainSafe/chainbridge-celo/chain/client"
+	"github.com/ChainSafe/chainbridge-celo/chain/config"
+	"github.com/ChainSafe/chainbridge-celo/chain/listener"
+	"github.com/ChainSafe/chainbridge-celo/chain/writer"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ChainSafe/chainbridge-celo/validatorsync"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// proposalHash rebuilds message of source deposit the way relayer listener does and computes data hash relayer
+// would vote for on destination chain
+func proposalHash(cctx *cli.Context) error {
+	url := cctx.String("url")
+	gasLimit := cctx.Uint64("gasLimit")
+	gasPrice := cctx.Uint64("gasPrice")
+	txHashFlag := cctx.String("txHash")
+	if txHashFlag == "" || cctx.String("sourceUrl") == "" {
+		return errors.New("--sourceUrl and --txHash should be provided")
+	}
+	txHash := common.HexToHash(txHashFlag)
+	sourceConfig := &config.CeloChainConfig{
+		ID:                     utils.ChainId(cctx.Uint64("sourceChainId")),
+		BridgeContract:         common.HexToAddress(cctx.String("sourceBridge")),
+		Erc20HandlerContract:   common.HexToAddress(cctx.String("sourceErc20Handler")),
+		Erc721HandlerContract:  common.HexToAddress(cctx.String("sourceErc721Handler")),
+		GenericHandlerContract: common.HexToAddress(cctx.String("sourceGenericHandler")),
+		ValidatorsSyncWorkers:  config.DefaultValidatorsSyncWorkers,
+	}
+
+	sourceClient, err := client.NewClient(cctx.String("sourceUrl"), false, nil, big.NewInt(0).SetUint64(gasLimit), big.NewInt(0).SetUint64(gasPrice), big.NewFloat(1))
+	if err != nil {
+		return err
+	}
+	defer sourceClient.Close()
+	err = sourceConfig.ResolveEpochSize(sourceClient)
+	if err != nil {
+		return err
+	}
+	receipt, err := sourceClient.TransactionReceipt(context.Background(), txHash)
+	if err != nil {
+		return fmt.Errorf("unable to fetch receipt of tx %s: %w", txHash.Hex(), err)
+	}
+	var dest utils.ChainId
+	var nonce utils.Nonce
+	found := false
+	for _, l := range receipt.Logs {
+		if l.Address == sourceConfig.BridgeContract && len(l.Topics) == 4 && l.Topics[0] == utils.Deposit.GetTopic() {
+			dest = utils.ChainId(l.Topics[1].Big().Uint64())
+			nonce = utils.Nonce(l.Topics[3].Big().Uint64())
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("tx %s has no Deposit event of bridge %s", txHash.Hex(), sourceConfig.BridgeContract.Hex())
+	}
+
+	// Validators are synced from genesis into memory, validators sync failure ends waiting for deposit epoch
+	validatorsStore := validatorsync.NewValidatorsStoreWithStorage(validatorsync.NewMemoryStorage())
+	defer validatorsStore.Close()
+	stopChn := make(chan struct{})
+	defer close(stopChn)
+	errChn := make(chan error, 1)
+	go validatorsync.SyncBlockValidators(stopChn, errChn, sourceClient, validatorsStore, uint8(sourceConfig.ID), sourceConfig.EpochSize, sourceConfig.ValidatorsSyncWorkers)
+	synced := make(chan error, 1)
+	waitCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_, err := validatorsStore.GetAPKForBlock(waitCtx, receipt.BlockNumber, uint8(sourceConfig.ID), sourceConfig.EpochSize)
+		synced <- err
+	}()
+	log.Info().Str("block", receipt.BlockNumber.String()).Msg("Syncing validators of deposit block")
+	select {
+	case err := <-errChn:
+		return fmt.Errorf("validators sync failed: %w", err)
+	case err := <-synced:
+		if err != nil {
+			return err
+		}
+	}
+
+	l, err := newSourceListener(sourceConfig, sourceClient, validatorsStore, stopChn, errChn)
+	if err != nil {
+		return err
+	}
+	msgs, err := l.FetchDepositsInRange(receipt.BlockNumber, receipt.BlockNumber, &nonce)
+	if err != nil {
+		return err
+	}
+	var m *utils.Message
+	for _, msg := range msgs {
+		if msg.Destination == dest {
+			m = msg
+		}
+	}
+	if m == nil {
+		return fmt.Errorf("deposit with nonce %d to chain %d was not rebuilt, check source handler addresses", nonce, dest)
+	}
+
+	data, err := writer.ConstructProposalData(m)
+	if err != nil {
+		return err
+	}
+	handler, err := destinationHandler(cctx, url, m.ResourceId)
+	if err != nil {
+		return err
+	}
+	dataHash := writer.CreateProposalDataHash(data, handler, m.MPParams, m.SVParams)
+
+	decoded, err := writer.DecodeProposalData(m.Type, data)
+	if err != nil {
+		return err
+	}
+	logger := log.Info().Interface("src", m.Source).Interface("dst", m.Destination).Interface("nonce", m.DepositNonce).
+		Str("type", string(m.Type)).Str("rId", m.ResourceId.Hex()).Str("handler", handler.Hex()).
+		Str("recipient", common.Bytes2Hex(decoded.Recipient)).Str("data", common.Bytes2Hex(data))
+	if decoded.Amount != nil {
+		logger = logger.Str("amount", decoded.Amount.String())
+	}
+	if decoded.TokenID != nil {
+		logger = logger.Str("tokenId", decoded.TokenID.String())
+	}
+	if decoded.Metadata != nil {
+		logger = logger.Str("metadata", common.Bytes2Hex(decoded.Metadata))
+	}
+	logger.Str("dataHash", common.Bytes2Hex(dataHash[:])).Msg("Proposal data hash computed")
+	return nil
+}
+
+// destinationHandler returns --handler if provided, otherwise handler of resource registered on destination bridge
+func destinationHandler(cctx *cli.Context, url string, rId utils.ResourceId) (common.Address, error) {
+	if h := cctx.String("handler"); h != "" {
+		if !common.IsHexAddress(h) {
+			return common.Address{}, fmt.Errorf("invalid handler address %s", h)
+		}
+		return common.HexToAddress(h), nil
+	}
+	bridgeAddress, err := cliutils.DefineBridgeAddress(cctx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	destClient, err := client.NewClient(url, false, nil, big.NewInt(0), big.NewInt(0), big.NewFloat(1))
+	if err != nil {
+		return common.Address{}, err
+	}
+	defer destClient.Close()
+	bridgeContract, err := bridgeHandler.NewBridge(bridgeAddress, destClient)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return bridgeContract.ResourceIDToHandlerAddress(&bind.CallOpts{}, rId)
+}
+
+type depositsFetcher interface {
+	FetchDepositsInRange(from, to *big.Int, nonce *utils.Nonce) ([]*utils.Message, error)
+}
+
+// newSourceListener creates listener of source chain that is used only to rebuild deposits
+func newSourceListener(cfg *config.CeloChainConfig, c *client.Client, valsAggr listener.ValidatorsAggregator, stopChn <-chan struct{}, errChn chan<- error) (depositsFetcher, error) {
+	bridgeContract, err := bridgeHandler.NewBridge(cfg.BridgeContract, c)
+	if err != nil {
+		return nil, err
+	}
+	erc20HandlerContract, err := erc20Handler.NewERC20Handler(cfg.Erc20HandlerContract, c)
+	if err != nil {
+		return nil, err
+	}
+	erc721HandlerContract, err := erc721Handler.NewERC721Handler(cfg.Erc721HandlerContract, c)
+	if err != nil {
+		return nil, err
+	}
+	genericHandlerContract, err := GenericHandler.NewGenericHandler(cfg.GenericHandlerContract, c)
+	if err != nil {
+		return nil, err
+	}
+	l := listener.NewListener(cfg, c, nil, stopChn, errChn, nil, valsAggr)
+	l.SetContracts(bridgeContract, erc20HandlerContract, erc721HandlerContract, genericHandlerContract)
+	return l, nil
+}
+
+var proposalHashCMD = &cli.Command{
+	Name:        "proposal-hash",
+	Description: "Computes proposal data hash relayer votes for from source chain deposit. Validators of deposit block are synced from source chain into memory.",
+	Action:      proposalHash,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "sourceUrl",
+			Usage: "RPC url of source chain node",
+		},
+		&cli.StringFlag{
+			Name:  "txHash",
+			Usage: "Hash of deposit transaction on source chain",
+		},
+		&cli.Uint64Flag{
+			Name:  "sourceChainId",
+			Usage: "Chain ID of source chain",
+		},
+		&cli.StringFlag{
+			Name:  "sourceBridge",
+			Usage: "Bridge contract address on source chain",
+		},
+		&cli.StringFlag{
+			Name:  "sourceErc20Handler",
+			Usage: "ERC20 handler address on source chain",
+		},
+		&cli.StringFlag{
+			Name:  "sourceErc721Handler",
+			Usage: "ERC721 handler address on source chain",
+		},
+		&cli.StringFlag{
+			Name:  "sourceGenericHandler",
+			Usage: "Generic handler address on source chain",
+		},
+		&cli.StringFlag{
+			Name:  "bridge",
+			Usage: "Bridge contract address on destination chain, used to look up handler of resource",
+		},
+		&cli.StringFlag{
+			Name:  "handler",
+			Usage: "Handler address on destination chain as configured in relayer (default: handler of resource on destination bridge)",
+		},
+	},
+}
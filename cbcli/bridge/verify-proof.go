@@ -0,0 +1,114 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/chainbridge-celo/chain/client"
+	"github.com/ChainSafe/chainbridge-celo/txtrie"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+func verifyProof(cctx *cli.Context) error {
+	url := cctx.String("url")
+	gasLimit := cctx.Uint64("gasLimit")
+	gasPrice := cctx.Uint64("gasPrice")
+	txHashFlag := cctx.String("txHash")
+	if txHashFlag == "" {
+		return errors.New("--txHash should be provided")
+	}
+	txHash := common.HexToHash(txHashFlag)
+
+	ethClient, err := client.NewClient(url, false, nil, big.NewInt(0).SetUint64(gasLimit), big.NewInt(0).SetUint64(gasPrice), big.NewFloat(1))
+	if err != nil {
+		return err
+	}
+	receipt, err := ethClient.TransactionReceipt(context.Background(), txHash)
+	if err != nil {
+		return fmt.Errorf("unable to fetch receipt of tx %s: %w", txHash.Hex(), err)
+	}
+	block, err := ethClient.BlockByNumber(context.Background(), receipt.BlockNumber)
+	if err != nil {
+		return err
+	}
+	root := block.TxHash()
+
+	// Proof from failed execution is verified as is, otherwise it is rebuilt the way relayer does
+	mp := &utils.MerkleProof{TxRootHash: root}
+	if cctx.IsSet("nodes") {
+		mp.Nodes = common.FromHex(cctx.String("nodes"))
+		mp.Key = common.FromHex(cctx.String("key"))
+		if cctx.IsSet("root") {
+			mp.TxRootHash = common.HexToHash(cctx.String("root"))
+		}
+	} else {
+		proof, err := rebuildProof(block, receipt.TransactionIndex)
+		if err != nil {
+			return err
+		}
+		mp.Nodes, mp.Key = proof.Nodes, proof.Key
+	}
+	log.Info().Str("tx", txHash.Hex()).Str("block", block.Number().String()).Uint("txIndex", receipt.TransactionIndex).
+		Str("root", common.Bytes2Hex(mp.TxRootHash[:])).Str("key", common.Bytes2Hex(mp.Key)).Str("nodes", common.Bytes2Hex(mp.Nodes)).Msg("Verifying proof")
+
+	if mp.TxRootHash != root {
+		log.Warn().Str("expected", root.Hex()).Msg("Proof root differs from block root")
+	}
+	expectedKey, err := txtrie.KeyForIndex(receipt.TransactionIndex)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expectedKey, mp.Key) {
+		log.Warn().Str("expected", common.Bytes2Hex(expectedKey)).Msg("Proof key differs from tx index key")
+	}
+
+	proven, err := txtrie.VerifyTxProof(mp)
+	if err != nil {
+		return err
+	}
+	if proven.Hash() != txHash {
+		return fmt.Errorf("proof is valid but proves tx %s instead of %s", proven.Hash().Hex(), txHash.Hex())
+	}
+	log.Info().Str("provenTx", proven.Hash().Hex()).Msg("Proof is valid, proven transaction decoded")
+	return nil
+}
+
+// rebuildProof builds transactions trie of block the way relayer listener does and retrieves proof of tx with index
+func rebuildProof(block *types.Block, index uint) (*txtrie.Proof, error) {
+	trie, err := txtrie.NewTrie(block.TxHash(), block.Transactions())
+	if err != nil {
+		return nil, err
+	}
+	return trie.Prove(index)
+}
+
+var verifyProofCMD = &cli.Command{
+	Name:        "verify-proof",
+	Description: "Verifies merkle proof of deposit transaction offline. Proof is rebuilt from source chain block unless --nodes is provided.",
+	Action:      verifyProof,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "txHash",
+			Usage: "Hash of deposit transaction on source chain",
+		},
+		&cli.StringFlag{
+			Name:  "nodes",
+			Usage: "Proof nodes to verify, eg. from failed executeProposal input",
+		},
+		&cli.StringFlag{
+			Name:  "key",
+			Usage: "Proof key to verify, used with --nodes",
+		},
+		&cli.StringFlag{
+			Name:  "root",
+			Usage: "Proof root hash to verify, used with --nodes (default: root of deposit block)",
+		},
+	},
+}
@@ -27,6 +27,9 @@ const DefaultGasPrice = 20000000000
 
 var BlockRetryInterval = time.Second * 5
 
+// Celo precompiled contract returning epoch size of the chain
+var epochSizeAddress = ethcommon.BytesToAddress([]byte{0xff - 7})
+
 type Client struct {
 	*ethclient.Client
 	endpoint      string
@@ -47,6 +50,7 @@ type LogFilterWithLatestBlock interface {
 	FilterLogs(ctx context.Context, q eth.FilterQuery) ([]types.Log, error)
 	LatestBlock() (*big.Int, error)
 	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
 }
 
 // NewConnection returns an uninitialized connection, must call Client.Connect() before using.
@@ -82,6 +86,12 @@ func (c *Client) Connect() error {
 	}
 	c.Client = ethclient.NewClient(rpcClient)
 
+	// Client without keypair is read-only and can not submit transactions
+	if c.kp == nil {
+		c.callOpts = &bind.CallOpts{}
+		return nil
+	}
+
 	// Construct tx opts, call opts, and nonce mechanism
 	opts, _, err := c.newTransactOpts(c.gasLimit, c.maxGasPrice)
 	if err != nil {
@@ -171,6 +181,22 @@ func (c *Client) LatestBlock() (*big.Int, error) {
 	return header.Number, nil
 }
 
+// EpochSize returns Istanbul epoch size of the chain queried from epoch size precompiled contract
+func (c *Client) EpochSize(ctx context.Context) (uint64, error) {
+	res, err := c.CallContract(ctx, eth.CallMsg{To: &epochSizeAddress}, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(res) != 32 {
+		return 0, fmt.Errorf("unexpected epoch size precompile result %x, endpoint should be Celo node", res)
+	}
+	size := new(big.Int).SetBytes(res)
+	if !size.IsUint64() || size.Sign() == 0 {
+		return 0, fmt.Errorf("invalid epoch size %s", size)
+	}
+	return size.Uint64(), nil
+}
+
 // EnsureHasBytecode asserts if contract code exists at the specified address
 func (c *Client) EnsureHasBytecode(addr ethcommon.Address) error {
 	code, err := c.CodeAt(context.Background(), addr, nil)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ChainSafe/chainbridge-utils/crypto/secp256k1"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
 )
 
 func Test_ClientOpts(t *testing.T) {
@@ -64,3 +65,37 @@ func Test_ClientWithArgs(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+type deployedAt struct {
+	block *big.Int
+	calls int
+}
+
+func (d *deployedAt) CodeAt(ctx context.Context, contract ethcommon.Address, blockNumber *big.Int) ([]byte, error) {
+	d.calls++
+	if blockNumber.Cmp(d.block) < 0 {
+		return []byte{}, nil
+	}
+	return []byte{0x60, 0x80}, nil
+}
+
+func Test_FindDeploymentBlock(t *testing.T) {
+	for _, block := range []int64{0, 1, 4242, 999999, 1000000} {
+		d := &deployedAt{block: big.NewInt(block)}
+		found, err := FindDeploymentBlock(context.Background(), d, ethcommon.Address{0x01}, big.NewInt(1000000))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Cmp(big.NewInt(block)) != 0 {
+			t.Fatalf("expected deployment block %d got %s", block, found)
+		}
+		if d.calls > 22 {
+			t.Fatalf("expected binary search got %d calls", d.calls)
+		}
+	}
+
+	_, err := FindDeploymentBlock(context.Background(), &deployedAt{block: big.NewInt(2000000)}, ethcommon.Address{0x01}, big.NewInt(1000000))
+	if err == nil {
+		t.Fatal("expected no bytecode error got nil")
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+type CodeAtGetter interface {
+	CodeAt(ctx context.Context, contract ethcommon.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// FindDeploymentBlock returns first block contract has bytecode at by binary search over blocks up to latest.
+// Node should keep state of searched blocks, eg. archive node
+func FindDeploymentBlock(ctx context.Context, c CodeAtGetter, contract ethcommon.Address, latest *big.Int) (*big.Int, error) {
+	code, err := c.CodeAt(ctx, contract, latest)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("no bytecode found at %s", contract.Hex())
+	}
+	low, high := big.NewInt(0), new(big.Int).Set(latest)
+	for low.Cmp(high) < 0 {
+		mid := new(big.Int).Rsh(new(big.Int).Add(low, high), 1)
+		code, err := c.CodeAt(ctx, contract, mid)
+		if err != nil {
+			return nil, err
+		}
+		if len(code) == 0 {
+			low = mid.Add(mid, big.NewInt(1))
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
@@ -80,3 +80,18 @@ func (mr *MockLogFilterWithLatestBlockMockRecorder) BlockByNumber(ctx, number in
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockByNumber", reflect.TypeOf((*MockLogFilterWithLatestBlock)(nil).BlockByNumber), ctx, number)
 }
+
+// HeaderByNumber mocks base method
+func (m *MockLogFilterWithLatestBlock) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeaderByNumber", ctx, number)
+	ret0, _ := ret[0].(*types.Header)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeaderByNumber indicates an expected call of HeaderByNumber
+func (mr *MockLogFilterWithLatestBlockMockRecorder) HeaderByNumber(ctx, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeaderByNumber", reflect.TypeOf((*MockLogFilterWithLatestBlock)(nil).HeaderByNumber), ctx, number)
+}
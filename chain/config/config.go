@@ -4,13 +4,18 @@
 package config
 
 import (
+	"context"
+	"fmt"
 	"math/big"
 	"strconv"
 
+	"github.com/ChainSafe/chainbridge-celo/approval"
 	"github.com/ChainSafe/chainbridge-celo/chain/client"
 	"github.com/ChainSafe/chainbridge-celo/cmd/cfg"
 	"github.com/ChainSafe/chainbridge-celo/flags"
+	"github.com/ChainSafe/chainbridge-celo/limiter"
 	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ChainSafe/chainbridge-celo/validatorsync"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
@@ -19,6 +24,7 @@ import (
 const DefaultGasLimit = 6721975
 const DefaultGasPrice = 20000000000
 const DefaultGasMultiplier = 1
+const DefaultValidatorsSyncWorkers = 4
 
 type CeloChainConfig struct {
 	ID                     utils.ChainId // ChainID
@@ -38,8 +44,14 @@ type CeloChainConfig struct {
 	StartBlock             *big.Int
 	LatestBlock            bool
 	Insecure               bool
-	EpochSize              uint64 // Size of chain epoch. eg. The number of blocks after which to checkpoint and reset the pending votes
+	EpochSize              uint64 // Size of chain epoch. eg. The number of blocks after which to checkpoint and reset the pending votes. Detected from chain if not set
 	GasMultiplier          *big.Float
+	VolumeLimits           []*limiter.Limit              // Rolling window limits per resource id enforced before voting
+	VolumeLimitPause       bool                          // Pause bridge transfers when volume limit is exceeded. Requires admin role
+	ApprovalThresholds     map[utils.ResourceId]*big.Int // Transfers above threshold of its resource require manual approval before voting
+	ValidatorsCheckpoint   *validatorsync.Checkpoint     // Trusted validators set validators sync starts from
+	ValidatorsSyncWorkers  int                           // Number of epoch headers fetched in parallel by validators sync
+	ValidatorsRetention    *validatorsync.Retention      // Validators history kept in store, nil keeps everything
 }
 
 func (cfg *CeloChainConfig) EnsureContractsHaveBytecode(conn *client.Client) error {
@@ -62,6 +74,24 @@ func (cfg *CeloChainConfig) EnsureContractsHaveBytecode(conn *client.Client) err
 	return nil
 }
 
+type EpochSizeGetter interface {
+	EpochSize(ctx context.Context) (uint64, error)
+}
+
+// ResolveEpochSize sets epoch size detected from the chain. Configured epoch size is only used as a cross-check
+// and start is refused when it differs from the chain one
+func (cfg *CeloChainConfig) ResolveEpochSize(conn EpochSizeGetter) error {
+	detected, err := conn.EpochSize(context.Background())
+	if err != nil {
+		return errors.Wrap(err, "unable to detect epoch size")
+	}
+	if cfg.EpochSize != 0 && cfg.EpochSize != detected {
+		return fmt.Errorf("configured epochSize %d differs from chain epoch size %d", cfg.EpochSize, detected)
+	}
+	cfg.EpochSize = detected
+	return nil
+}
+
 // parseChainConfig uses a core.ChainConfig to construct a corresponding Config
 func ParseChainConfig(rawCfg *cfg.RawChainConfig, ctx *cli.Context) (*CeloChainConfig, error) {
 	var ks string
@@ -97,17 +127,17 @@ func ParseChainConfig(rawCfg *cfg.RawChainConfig, ctx *cli.Context) (*CeloChainC
 		Http:                   false,
 		StartBlock:             big.NewInt(0),
 		Insecure:               insecure,
+		ValidatorsSyncWorkers:  DefaultValidatorsSyncWorkers,
 	}
 
-	epochSize, ok := rawCfg.Opts["epochSize"]
-	if !ok {
-		return nil, errors.New("Set epochSize")
-	}
-	epochSizeUint, err := strconv.ParseUint(epochSize, 10, 64)
-	if err != nil {
-		return nil, err
+	// Epoch size is detected from the chain on start, configured one is cross-checked against it
+	if epochSize, ok := rawCfg.Opts["epochSize"]; ok && epochSize != "" {
+		epochSizeUint, err := strconv.ParseUint(epochSize, 10, 64)
+		if err != nil || epochSizeUint == 0 {
+			return nil, errors.New("unable to parse epochSize, should be positive number")
+		}
+		config.EpochSize = epochSizeUint
 	}
-	config.EpochSize = epochSizeUint
 
 	if contract, ok := rawCfg.Opts["bridge"]; ok && contract != "" {
 		config.BridgeContract = common.HexToAddress(contract)
@@ -167,5 +197,58 @@ func ParseChainConfig(rawCfg *cfg.RawChainConfig, ctx *cli.Context) (*CeloChainC
 			return nil, errors.New("unable to parse start block")
 		}
 	}
+
+	if volumeLimits, ok := rawCfg.Opts["volumeLimits"]; ok && volumeLimits != "" {
+		limits, err := limiter.ParseLimits(volumeLimits)
+		if err != nil {
+			return nil, err
+		}
+		config.VolumeLimits = limits
+	}
+
+	if pause, ok := rawCfg.Opts["volumeLimitPause"]; ok && pause == "true" {
+		config.VolumeLimitPause = true
+	}
+
+	if thresholds, ok := rawCfg.Opts["approvalThresholds"]; ok && thresholds != "" {
+		config.ApprovalThresholds, err = approval.ParseThresholds(thresholds)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if checkpoint, ok := rawCfg.Opts["validatorsCheckpoint"]; ok && checkpoint != "" {
+		config.ValidatorsCheckpoint, err = validatorsync.ParseCheckpoint(checkpoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if workers, ok := rawCfg.Opts["validatorsSyncWorkers"]; ok && workers != "" {
+		n, err := strconv.Atoi(workers)
+		if err != nil || n < 1 {
+			return nil, errors.New("unable to parse validatorsSyncWorkers, should be positive number")
+		}
+		config.ValidatorsSyncWorkers = n
+	}
+
+	if epochs, ok := rawCfg.Opts["validatorsRetentionEpochs"]; ok && epochs != "" {
+		n, err := strconv.ParseUint(epochs, 10, 64)
+		if err != nil || n < 1 {
+			return nil, errors.New("unable to parse validatorsRetentionEpochs, should be positive number")
+		}
+		config.ValidatorsRetention = &validatorsync.Retention{Epochs: n}
+	}
+
+	if margin, ok := rawCfg.Opts["validatorsRetentionMargin"]; ok && margin != "" {
+		m, ok := new(big.Int).SetString(margin, 10)
+		if !ok || m.Sign() < 0 {
+			return nil, errors.New("unable to parse validatorsRetentionMargin, should be non-negative number of blocks")
+		}
+		if config.ValidatorsRetention == nil {
+			config.ValidatorsRetention = &validatorsync.Retention{}
+		}
+		config.ValidatorsRetention.Margin = m
+	}
 	return config, nil
 }
@@ -3,6 +3,8 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"math/big"
 	"strconv"
@@ -445,3 +447,212 @@ func TestParseConfigInsecureKeyIsSetFalse(t *testing.T) {
 	}
 
 }
+
+func TestParseConfigVolumeLimits(t *testing.T) {
+	rCon := &cfg.RawChainConfig{
+		Name:     "test",
+		Type:     "test",
+		Id:       "3",
+		Endpoint: "http://localhost:8080",
+		From:     "0x18DfB0f9B4138d70d3EFe504A4D716D483Cfa202",
+		Opts: map[string]string{
+			"bridge":             "0x18DfB0f9B4138d70d3EFe504A4D716D483Cfa202",
+			"epochSize":          "12",
+			"volumeLimits":       "0x0000000000000000000000000000000000000000000000000000000000000001:1000:10:1h",
+			"volumeLimitPause":   "true",
+			"approvalThresholds": "0x0000000000000000000000000000000000000000000000000000000000000001:500",
+		},
+	}
+
+	set := flag.NewFlagSet("test", 0)
+
+	ctx := cli.NewContext(nil, set, nil)
+
+	config, err := ParseChainConfig(rCon, ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(config.VolumeLimits) != 1 {
+		t.Fatalf("expected 1 volume limit got %v", len(config.VolumeLimits))
+	}
+
+	if config.VolumeLimits[0].MaxTransfers != 10 {
+		t.Errorf("expected MaxTransfers %v got %v ", 10, config.VolumeLimits[0].MaxTransfers)
+	}
+
+	if !config.VolumeLimitPause {
+		t.Errorf("expected VolumeLimitPause %v got %v ", true, config.VolumeLimitPause)
+	}
+
+	if len(config.ApprovalThresholds) != 1 {
+		t.Errorf("expected 1 approval threshold got %v", len(config.ApprovalThresholds))
+	}
+
+	rCon.Opts["volumeLimits"] = "0x01:1000:10:1h"
+	_, err = ParseChainConfig(rCon, ctx)
+	if err == nil {
+		t.Error("expected invalid volume limits error got nil")
+	}
+}
+
+func TestParseConfigValidatorsSyncWorkers(t *testing.T) {
+	rCon := &cfg.RawChainConfig{
+		Name:     "test",
+		Type:     "test",
+		Id:       "3",
+		Endpoint: "http://localhost:8080",
+		From:     "0x18DfB0f9B4138d70d3EFe504A4D716D483Cfa202",
+		Opts: map[string]string{
+			"bridge":    "0x18DfB0f9B4138d70d3EFe504A4D716D483Cfa202",
+			"epochSize": "12",
+		},
+	}
+
+	set := flag.NewFlagSet("test", 0)
+
+	ctx := cli.NewContext(nil, set, nil)
+
+	config, err := ParseChainConfig(rCon, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.ValidatorsSyncWorkers != DefaultValidatorsSyncWorkers {
+		t.Errorf("expected ValidatorsSyncWorkers %v got %v ", DefaultValidatorsSyncWorkers, config.ValidatorsSyncWorkers)
+	}
+
+	rCon.Opts["validatorsSyncWorkers"] = "8"
+	config, err = ParseChainConfig(rCon, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.ValidatorsSyncWorkers != 8 {
+		t.Errorf("expected ValidatorsSyncWorkers %v got %v ", 8, config.ValidatorsSyncWorkers)
+	}
+
+	rCon.Opts["validatorsSyncWorkers"] = "0"
+	_, err = ParseChainConfig(rCon, ctx)
+	if err == nil {
+		t.Error("expected invalid validatorsSyncWorkers error got nil")
+	}
+}
+
+func TestParseConfigValidatorsRetention(t *testing.T) {
+	rCon := &cfg.RawChainConfig{
+		Name:     "test",
+		Type:     "test",
+		Id:       "3",
+		Endpoint: "http://localhost:8080",
+		From:     "0x18DfB0f9B4138d70d3EFe504A4D716D483Cfa202",
+		Opts: map[string]string{
+			"bridge":    "0x18DfB0f9B4138d70d3EFe504A4D716D483Cfa202",
+			"epochSize": "12",
+		},
+	}
+
+	set := flag.NewFlagSet("test", 0)
+
+	ctx := cli.NewContext(nil, set, nil)
+
+	config, err := ParseChainConfig(rCon, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.ValidatorsRetention != nil {
+		t.Errorf("expected no ValidatorsRetention got %v", config.ValidatorsRetention)
+	}
+
+	rCon.Opts["validatorsRetentionEpochs"] = "100"
+	rCon.Opts["validatorsRetentionMargin"] = "1200"
+	config, err = ParseChainConfig(rCon, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.ValidatorsRetention.Epochs != 100 || config.ValidatorsRetention.Margin.Cmp(big.NewInt(1200)) != 0 {
+		t.Errorf("expected ValidatorsRetention of 100 epochs and 1200 blocks margin got %v", config.ValidatorsRetention)
+	}
+
+	rCon.Opts["validatorsRetentionMargin"] = "-1"
+	_, err = ParseChainConfig(rCon, ctx)
+	if err == nil {
+		t.Error("expected invalid validatorsRetentionMargin error got nil")
+	}
+
+	rCon.Opts["validatorsRetentionEpochs"] = "0"
+	_, err = ParseChainConfig(rCon, ctx)
+	if err == nil {
+		t.Error("expected invalid validatorsRetentionEpochs error got nil")
+	}
+}
+
+type epochSizeGetter struct {
+	size uint64
+	err  error
+}
+
+func (g *epochSizeGetter) EpochSize(ctx context.Context) (uint64, error) {
+	return g.size, g.err
+}
+
+func TestResolveEpochSize(t *testing.T) {
+	rCon := &cfg.RawChainConfig{
+		Name:     "test",
+		Type:     "test",
+		Id:       "3",
+		Endpoint: "http://localhost:8080",
+		From:     "0x18DfB0f9B4138d70d3EFe504A4D716D483Cfa202",
+		Opts: map[string]string{
+			"bridge": "0x18DfB0f9B4138d70d3EFe504A4D716D483Cfa202",
+		},
+	}
+
+	set := flag.NewFlagSet("test", 0)
+
+	ctx := cli.NewContext(nil, set, nil)
+
+	config, err := ParseChainConfig(rCon, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = config.ResolveEpochSize(&epochSizeGetter{size: 17280})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.EpochSize != 17280 {
+		t.Errorf("expected EpochSize %v got %v ", 17280, config.EpochSize)
+	}
+
+	rCon.Opts["epochSize"] = "12"
+	config, err = ParseChainConfig(rCon, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = config.ResolveEpochSize(&epochSizeGetter{size: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = config.ResolveEpochSize(&epochSizeGetter{size: 17280})
+	if err == nil {
+		t.Error("expected epoch size mismatch error got nil")
+	}
+
+	err = config.ResolveEpochSize(&epochSizeGetter{err: errors.New("method not found")})
+	if err == nil {
+		t.Error("expected epoch size detection error got nil")
+	}
+
+	rCon.Opts["epochSize"] = "0"
+	_, err = ParseChainConfig(rCon, ctx)
+	if err == nil {
+		t.Error("expected invalid epochSize error got nil")
+	}
+}
@@ -10,24 +10,37 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/ChainSafe/chainbridge-celo/blockdb"
 	"github.com/ChainSafe/chainbridge-celo/chain/client"
 	"github.com/ChainSafe/chainbridge-celo/chain/config"
+	"github.com/ChainSafe/chainbridge-celo/deadletter"
 	"github.com/ChainSafe/chainbridge-celo/txtrie"
 	"github.com/ChainSafe/chainbridge-celo/utils"
 	eth "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/rs/zerolog/log"
 )
 
 var BlockDelay = big.NewInt(1)
 var BlockRetryInterval = time.Second * 5
 var ErrFatalPolling = errors.New("listener block polling failed")
+var ErrChainMismatch = errors.New("node chain does not contain processed blocks")
 var ExpectedBlockTime = time.Second
 var BlockRetryLimit = 5
 
+// Time listener waits for validators of deposit block epoch to be synced
+var ValidatorsWaitTimeout = time.Minute
+
+// Number of blocks queried at once when searching for deposit
+var DepositSearchRange = big.NewInt(10000)
+var ErrDepositNotFound = errors.New("deposit not found on source chain")
+var ErrUnrecognizedHandler = errors.New("deposit has unrecognized handler")
+
+// Number of block tries with their proofs kept for retried blocks and rebuilt deposits
+var TrieCacheSize = 16
+
 type listener struct {
 	cfg                    *config.CeloChainConfig
 	router                 IRouter
@@ -40,19 +53,27 @@ type listener struct {
 	sysErr                 chan<- error // Reports fatal error to core
 	//latestBlock            *metrics.LatestBlock
 	//metrics                *metrics.ChainMetrics
-	client   client.LogFilterWithLatestBlock
-	valsAggr ValidatorsAggregator
+	client      client.LogFilterWithLatestBlock
+	valsAggr    ValidatorsAggregator
+	deadLetters DeadLetterRecorder
+	tries       *txtrie.Cache
 }
 
 type IRouter interface {
 	Send(msg *utils.Message) error
 }
 type Blockstorer interface {
-	StoreBlock(*big.Int) error
+	StoreBlock(block *big.Int, hash ethcommon.Hash) error
+	BlockHash(block *big.Int) (ethcommon.Hash, error)
+}
+
+// DeadLetterRecorder persists deposits that can not be routed to their destination
+type DeadLetterRecorder interface {
+	Record(dl *deadletter.DeadLetter) error
 }
 
 type ValidatorsAggregator interface {
-	GetAPKForBlock(block *big.Int, chainID uint8, epochSize uint64) ([]byte, error)
+	GetAPKForBlock(ctx context.Context, block *big.Int, chainID uint8, epochSize uint64) ([]byte, error)
 }
 
 func NewListener(cfg *config.CeloChainConfig, client client.LogFilterWithLatestBlock, bs Blockstorer, stop <-chan struct{}, sysErr chan<- error, router IRouter, valsAggr ValidatorsAggregator) *listener {
@@ -64,6 +85,7 @@ func NewListener(cfg *config.CeloChainConfig, client client.LogFilterWithLatestB
 		router:     router,
 		client:     client,
 		valsAggr:   valsAggr,
+		tries:      txtrie.NewCache(TrieCacheSize),
 	}
 }
 
@@ -74,8 +96,17 @@ func (l *listener) SetContracts(bridge IBridge, erc20Handler IERC20Handler, erc7
 	l.genericHandlerContract = genericHandler
 }
 
+// SetDeadLetters enables recording of deposits that can not be routed. Without it such deposits are only logged
+func (l *listener) SetDeadLetters(d DeadLetterRecorder) {
+	l.deadLetters = d
+}
+
 func (l *listener) StartPollingBlocks() error {
 	log.Debug().Msg("Starting listener...")
+	err := l.checkProcessedBlock(l.cfg.StartBlock)
+	if err != nil {
+		return err
+	}
 
 	go func() {
 		err := l.pollBlocks()
@@ -125,6 +156,27 @@ func (l *listener) pollBlocks() error {
 				continue
 			}
 
+			header, err := l.client.HeaderByNumber(context.Background(), currentBlock)
+			if err != nil {
+				log.Error().Err(err).Str("block", currentBlock.String()).Msg("Unable to get block header")
+				retry--
+				time.Sleep(BlockRetryInterval)
+				continue
+			}
+
+			err = l.checkParent(currentBlock, header)
+			if errors.Is(err, ErrChainMismatch) {
+				log.Error().Bool("alert", true).Err(err).Msg("Node chain differs from processed blocks, stopping listener")
+				l.sysErr <- err
+				return nil
+			}
+			if err != nil {
+				log.Error().Err(err).Str("block", currentBlock.String()).Msg("Unable to check block parent")
+				retry--
+				time.Sleep(BlockRetryInterval)
+				continue
+			}
+
 			// Parse out events
 			err = l.getDepositEventsAndProofsForBlock(currentBlock)
 			if err != nil {
@@ -138,8 +190,9 @@ func (l *listener) pollBlocks() error {
 				log.Debug().Str("block", currentBlock.String()).Msg("Queried block for deposit events")
 			}
 
-			// Write to block store. Not a critical operation, no need to retry
-			err = l.blockstore.StoreBlock(currentBlock)
+			// Write to block store. Not a critical operation, no need to retry. Checkpoint is written atomically,
+			// so failed write keeps previous checkpoint until the next block is stored
+			err = l.blockstore.StoreBlock(currentBlock, header.Hash())
 			if err != nil {
 				log.Error().Str("block", currentBlock.String()).Err(err).Msg("Failed to write latest block to blockstore")
 			}
@@ -159,6 +212,46 @@ func (l *listener) pollBlocks() error {
 	}
 }
 
+// checkProcessedBlock returns ErrChainMismatch if node hash of block differs from the one stored when it was processed.
+// Blocks not in processed blocks history are not checked
+func (l *listener) checkProcessedBlock(block *big.Int) error {
+	processed, err := l.blockstore.BlockHash(block)
+	if errors.Is(err, blockdb.ErrBlockNotInHistory) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	header, err := l.client.HeaderByNumber(context.Background(), block)
+	if err != nil {
+		return err
+	}
+	if header.Hash() != processed {
+		return fmt.Errorf("%w: block %s is %s, processed %s", ErrChainMismatch, block, header.Hash().Hex(), processed.Hex())
+	}
+	return nil
+}
+
+// checkParent returns ErrChainMismatch if parent of block header differs from processed previous block. Istanbul blocks
+// are final, so mismatch means node follows another chain than processed blocks were read from
+func (l *listener) checkParent(block *big.Int, header *types.Header) error {
+	if block.Sign() == 0 {
+		return nil
+	}
+	parent := new(big.Int).Sub(block, big.NewInt(1))
+	processed, err := l.blockstore.BlockHash(parent)
+	if errors.Is(err, blockdb.ErrBlockNotInHistory) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if header.ParentHash != processed {
+		return fmt.Errorf("%w: parent of block %s is %s, processed %s", ErrChainMismatch, block, header.ParentHash.Hex(), processed.Hex())
+	}
+	return nil
+}
+
 func (l *listener) getDepositEventsAndProofsForBlock(latestBlock *big.Int) error {
 	// querying for logs
 	query := buildQuery(l.cfg.BridgeContract, utils.Deposit, latestBlock, latestBlock)
@@ -173,71 +266,294 @@ func (l *listener) getDepositEventsAndProofsForBlock(latestBlock *big.Int) error
 	if err != nil {
 		return err
 	}
-	trie, err := txtrie.CreateNewTrie(blockData.TxHash(), blockData.Transactions())
+	trie, err := l.createBlockTrie(blockData)
 	if err != nil {
 		return err
 	}
-	// read through the log events and handle their deposit event if handler is recognized
+	// proofs of all deposits in block are retrieved in one pass
+	indexes := make([]uint, len(logs))
+	for i, eventLog := range logs {
+		indexes[i] = eventLog.TxIndex
+	}
+	proofs, err := trie.ProveIndexes(indexes)
+	if err != nil {
+		return err
+	}
+	// read through the log events and handle their deposit event, deposits that can not be routed are recorded as dead letters
 	for _, eventLog := range logs {
-
-		var m *utils.Message
-		destId := utils.ChainId(eventLog.Topics[1].Big().Uint64())
-		rId := utils.ResourceId(eventLog.Topics[2])
-		nonce := utils.Nonce(eventLog.Topics[3].Big().Uint64())
-
-		addr, err := l.bridgeContract.ResourceIDToHandlerAddress(&bind.CallOpts{}, rId)
-		if err != nil {
-			return fmt.Errorf("failed to get handler from resource ID %x, reason: %w", rId, err)
-		}
-		if addr == l.cfg.Erc20HandlerContract {
-			m, err = l.handleErc20DepositedEvent(destId, nonce)
-		} else if addr == l.cfg.Erc721HandlerContract {
-			m, err = l.handleErc721DepositedEvent(destId, nonce)
-		} else if addr == l.cfg.GenericHandlerContract {
-			m, err = l.handleGenericDepositedEvent(destId, nonce)
-		} else {
-			log.Error().Err(err).Str("handler", addr.Hex()).Msg("event has unrecognized handler")
-			return nil
+		m, err := l.buildDepositMessage(eventLog, blockData, proofs[eventLog.TxIndex])
+		if errors.Is(err, ErrUnrecognizedHandler) {
+			err = l.recordDeadLetter(eventLog, nil, err)
+			if err != nil {
+				return err
+			}
+			continue
 		}
 		if err != nil {
 			return err
 		}
-		apk, err := l.valsAggr.GetAPKForBlock(latestBlock, uint8(l.cfg.ID), l.cfg.EpochSize)
+		err = l.router.Send(m)
 		if err != nil {
-			return err
+			log.Error().Err(err).Msg("subscription error: failed to route message")
+			err = l.recordDeadLetter(eventLog, m, err)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
 
+// recordDeadLetter records deposit of eventLog that could not be routed because of reason. m is nil if message was not built
+func (l *listener) recordDeadLetter(eventLog types.Log, m *utils.Message, reason error) error {
+	if l.deadLetters == nil {
+		log.Error().Err(reason).Uint64("block", eventLog.BlockNumber).Str("tx", eventLog.TxHash.Hex()).Msg("Skipping deposit")
+		return nil
+	}
+	err := l.deadLetters.Record(&deadletter.DeadLetter{
+		Source:      l.cfg.ID,
+		Destination: utils.ChainId(eventLog.Topics[1].Big().Uint64()),
+		Nonce:       utils.Nonce(eventLog.Topics[3].Big().Uint64()),
+		ResourceID:  utils.ResourceId(eventLog.Topics[2]),
+		Block:       eventLog.BlockNumber,
+		BlockHash:   eventLog.BlockHash,
+		TxHash:      eventLog.TxHash,
+		TxIndex:     eventLog.TxIndex,
+		Reason:      reason.Error(),
+		Message:     m,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to record dead letter: %w", err)
+	}
+	return nil
+}
+
+// validatorsContext returns context that is cancelled after ValidatorsWaitTimeout or when listener stops
+func (l *listener) validatorsContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), ValidatorsWaitTimeout)
+	go func() {
+		select {
+		case <-l.stop:
+			cancel()
+		case <-ctx.Done():
 		}
-		keyRlp, err := rlp.EncodeToBytes(eventLog.TxIndex)
-		if err != nil {
-			return fmt.Errorf("encoding TxIndex to rlp: %w", err)
+	}()
+	return ctx, cancel
+}
+
+// buildDepositMessage constructs message for deposit eventLog included in blockData with proof of its transaction.
+// ErrUnrecognizedHandler is returned if deposit handler is not recognized
+func (l *listener) buildDepositMessage(eventLog types.Log, blockData *types.Block, proof *txtrie.Proof) (*utils.Message, error) {
+	var m *utils.Message
+	destId := utils.ChainId(eventLog.Topics[1].Big().Uint64())
+	rId := utils.ResourceId(eventLog.Topics[2])
+	nonce := utils.Nonce(eventLog.Topics[3].Big().Uint64())
+
+	addr, err := l.bridgeContract.ResourceIDToHandlerAddress(&bind.CallOpts{}, rId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get handler from resource ID %x, reason: %w", rId, err)
+	}
+	if addr == l.cfg.Erc20HandlerContract {
+		m, err = l.handleErc20DepositedEvent(destId, nonce)
+	} else if addr == l.cfg.Erc721HandlerContract {
+		m, err = l.handleErc721DepositedEvent(destId, nonce)
+	} else if addr == l.cfg.GenericHandlerContract {
+		m, err = l.handleGenericDepositedEvent(destId, nonce)
+	} else {
+		return nil, fmt.Errorf("%w %s", ErrUnrecognizedHandler, addr.Hex())
+	}
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := l.validatorsContext()
+	defer cancel()
+	apk, err := l.valsAggr.GetAPKForBlock(ctx, blockData.Number(), uint8(l.cfg.ID), l.cfg.EpochSize)
+	if err != nil {
+		return nil, err
+
+	}
+
+	// fetch IstanbulExtra data by parsing block header
+	// https://github.com/celo-org/celo-blockchain/blob/master/core/types/istanbul.go#L128-L142
+	extra, err := types.ExtractIstanbulExtra(blockData.Header())
+	if err != nil {
+		return nil, err
+	}
+
+	// RLP encode data in block header
+	rlpEncodedHeader, err := utils.RlpEncodeHeader(blockData.Header())
+	if err != nil {
+		return nil, err
+	}
+
+	m.SVParams = &utils.SignatureVerification{AggregatePublicKey: apk, BlockHash: blockData.Header().Hash(), Signature: extra.AggregatedSeal.Signature, RLPHeader: rlpEncodedHeader}
+	m.MPParams = &utils.MerkleProof{TxRootHash: utils.SliceTo32Bytes(blockData.TxHash().Bytes()), Nodes: proof.Nodes, Key: proof.Key}
+	return m, nil
+}
+
+// createBlockTrie builds transactions trie deposit proofs are retrieved from and verifies it against block header root.
+// Tries are cached by block hash
+func (l *listener) createBlockTrie(blockData *types.Block) (*txtrie.Trie, error) {
+	if trie, ok := l.tries.Get(blockData.Hash()); ok {
+		return trie, nil
+	}
+	trie, err := txtrie.NewTrie(blockData.TxHash(), blockData.Transactions())
+	if err != nil {
+		return nil, err
+	}
+	l.tries.Add(blockData.Hash(), trie)
+	return trie, nil
+}
+
+// FetchDeposit searches source chain for deposit with nonce to destination chain dest and rebuilds its message with proofs.
+// Blocks are searched backwards from the latest one in ranges of DepositSearchRange blocks. Deposit nonces grow with
+// blocks, so search stops at the previous deposit to dest
+func (l *listener) FetchDeposit(dest utils.ChainId, nonce utils.Nonce) (*utils.Message, error) {
+	latestBlock, err := l.client.LatestBlock()
+	if err != nil {
+		return nil, err
+	}
+	nonces := []ethcommon.Hash{ethcommon.BigToHash(nonce.Big())}
+	if nonce > 1 {
+		nonces = append(nonces, ethcommon.BigToHash((nonce - 1).Big()))
+	}
+	to := new(big.Int).Set(latestBlock)
+	for to.Sign() >= 0 {
+		from := new(big.Int).Sub(to, DepositSearchRange)
+		if from.Sign() < 0 {
+			from = big.NewInt(0)
 		}
-		proof, key, err := txtrie.RetrieveProof(trie, keyRlp)
+		query := buildQuery(l.cfg.BridgeContract, utils.Deposit, from, to)
+		query.Topics = append(query.Topics,
+			[]ethcommon.Hash{ethcommon.BigToHash(big.NewInt(int64(dest)))},
+			nil,
+			nonces,
+		)
+		logs, err := l.client.FilterLogs(context.Background(), query)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("unable to Filter Logs: %w", err)
+		}
+		for _, eventLog := range logs {
+			if utils.Nonce(eventLog.Topics[3].Big().Uint64()) == nonce {
+				return l.rebuildDeposit(eventLog)
+			}
 		}
+		if len(logs) > 0 {
+			// Only the previous deposit is in range, deposit with nonce was not made yet
+			return nil, ErrDepositNotFound
+		}
+		to = from.Sub(from, big.NewInt(1))
+	}
+	return nil, ErrDepositNotFound
+}
 
-		// fetch IstanbulExtra data by parsing block header
-		// https://github.com/celo-org/celo-blockchain/blob/master/core/types/istanbul.go#L128-L142
-		extra, err := types.ExtractIstanbulExtra(blockData.Header())
+// FetchDepositsInRange rebuilds messages with proofs for deposits made between from and to blocks inclusive.
+// If nonce is provided only deposits with that nonce are rebuilt. Deposits with unrecognized handler are skipped
+func (l *listener) FetchDepositsInRange(from, to *big.Int, nonce *utils.Nonce) ([]*utils.Message, error) {
+	msgs := make([]*utils.Message, 0)
+	for start := new(big.Int).Set(from); start.Cmp(to) <= 0; start.Add(start, DepositSearchRange) {
+		end := new(big.Int).Add(start, DepositSearchRange)
+		end.Sub(end, big.NewInt(1))
+		if end.Cmp(to) > 0 {
+			end.Set(to)
+		}
+		query := buildQuery(l.cfg.BridgeContract, utils.Deposit, start, end)
+		if nonce != nil {
+			query.Topics = append(query.Topics, nil, nil, []ethcommon.Hash{ethcommon.BigToHash(nonce.Big())})
+		}
+		logs, err := l.client.FilterLogs(context.Background(), query)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("unable to Filter Logs: %w", err)
 		}
+		// Logs are ordered by block, deposits of the same block share block data, trie and proofs pass
+		for len(logs) > 0 {
+			n := 1
+			for n < len(logs) && logs[n].BlockNumber == logs[0].BlockNumber {
+				n++
+			}
+			blockMsgs, err := l.buildBlockDeposits(logs[:n])
+			if err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, blockMsgs...)
+			logs = logs[n:]
+		}
+	}
+	return msgs, nil
+}
 
-		// RLP encode data in block header
-		rlpEncodedHeader, err := utils.RlpEncodeHeader(blockData.Header())
+// buildBlockDeposits rebuilds messages of deposit logs made in the same block, retrieving their proofs in one pass.
+// Deposits with unrecognized handler are skipped
+func (l *listener) buildBlockDeposits(logs []types.Log) ([]*utils.Message, error) {
+	blockData, err := l.client.BlockByNumber(context.Background(), new(big.Int).SetUint64(logs[0].BlockNumber))
+	if err != nil {
+		return nil, err
+	}
+	trie, err := l.createBlockTrie(blockData)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]uint, len(logs))
+	for i, eventLog := range logs {
+		indexes[i] = eventLog.TxIndex
+	}
+	proofs, err := trie.ProveIndexes(indexes)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]*utils.Message, 0, len(logs))
+	for _, eventLog := range logs {
+		m, err := l.buildDepositMessage(eventLog, blockData, proofs[eventLog.TxIndex])
+		if errors.Is(err, ErrUnrecognizedHandler) {
+			log.Error().Err(err).Uint64("block", eventLog.BlockNumber).Str("tx", eventLog.TxHash.Hex()).Msg("Skipping deposit")
+			continue
+		}
 		if err != nil {
-			return err
+			return nil, err
 		}
+		msgs = append(msgs, m)
+	}
+	return msgs, nil
+}
 
-		m.SVParams = &utils.SignatureVerification{AggregatePublicKey: apk, BlockHash: blockData.Header().Hash(), Signature: extra.AggregatedSeal.Signature, RLPHeader: rlpEncodedHeader}
-		m.MPParams = &utils.MerkleProof{TxRootHash: utils.SliceTo32Bytes(blockData.TxHash().Bytes()), Nodes: proof, Key: key}
-		err = l.router.Send(m)
+// RetryDeadLetter rebuilds message of dead letter deposit from its block and routes it again
+func (l *listener) RetryDeadLetter(dl *deadletter.DeadLetter) error {
+	block := new(big.Int).SetUint64(dl.Block)
+	query := buildQuery(l.cfg.BridgeContract, utils.Deposit, block, block)
+	query.Topics = append(query.Topics,
+		[]ethcommon.Hash{ethcommon.BigToHash(big.NewInt(int64(dl.Destination)))},
+		nil,
+		[]ethcommon.Hash{ethcommon.BigToHash(dl.Nonce.Big())},
+	)
+	logs, err := l.client.FilterLogs(context.Background(), query)
+	if err != nil {
+		return fmt.Errorf("unable to Filter Logs: %w", err)
+	}
+	if len(logs) == 0 {
+		return ErrDepositNotFound
+	}
+	m, err := l.rebuildDeposit(logs[0])
+	if err != nil {
+		return err
+	}
+	return l.router.Send(m)
+}
 
-		if err != nil {
-			log.Error().Err(err).Msg("subscription error: failed to route message")
-		}
+// rebuildDeposit fetches block of deposit eventLog and builds its message with proofs
+func (l *listener) rebuildDeposit(eventLog types.Log) (*utils.Message, error) {
+	blockData, err := l.client.BlockByNumber(context.Background(), new(big.Int).SetUint64(eventLog.BlockNumber))
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	trie, err := l.createBlockTrie(blockData)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := trie.Prove(eventLog.TxIndex)
+	if err != nil {
+		return nil, err
+	}
+	return l.buildDepositMessage(eventLog, blockData, proof)
 }
 
 // buildQuery constructs a query for the bridgeContract by hashing sig to get the event topic
@@ -10,9 +10,11 @@ import (
 	"github.com/ChainSafe/chainbridge-celo/bindings/ERC20Handler"
 	"github.com/ChainSafe/chainbridge-celo/bindings/ERC721Handler"
 	"github.com/ChainSafe/chainbridge-celo/bindings/GenericHandler"
+	"github.com/ChainSafe/chainbridge-celo/blockdb"
 	mock_client "github.com/ChainSafe/chainbridge-celo/chain/client/mock"
 	"github.com/ChainSafe/chainbridge-celo/chain/config"
 	mock_listener "github.com/ChainSafe/chainbridge-celo/chain/listener/mock"
+	"github.com/ChainSafe/chainbridge-celo/deadletter"
 	"github.com/ChainSafe/chainbridge-celo/txtrie"
 	"github.com/ChainSafe/chainbridge-celo/utils"
 	eth "github.com/ethereum/go-ethereum"
@@ -40,6 +42,7 @@ type ListenerTestSuite struct {
 	erc721Handler            *mock_listener.MockIERC721Handler
 	genericHandler           *mock_listener.MockIGenericHandler
 	validatorsAggregatorMock *mock_listener.MockValidatorsAggregator
+	deadLettersMock          *mock_listener.MockDeadLetterRecorder
 }
 
 func TestRunTestSuite(t *testing.T) {
@@ -59,6 +62,7 @@ func (s *ListenerTestSuite) SetupTest() {
 	s.erc721Handler = mock_listener.NewMockIERC721Handler(gomockController)
 	s.genericHandler = mock_listener.NewMockIGenericHandler(gomockController)
 	s.validatorsAggregatorMock = mock_listener.NewMockValidatorsAggregator(gomockController)
+	s.deadLettersMock = mock_listener.NewMockDeadLetterRecorder(gomockController)
 }
 func (s *ListenerTestSuite) TearDownTest() {}
 
@@ -83,7 +87,8 @@ func dummyBlockWithIstanbulExtra(number int64) *types.Block {
 	feeCurrencyAddr := common.HexToAddress("02")
 	gatewayFeeRecipientAddr := common.HexToAddress("03")
 	tx := types.NewTransaction(1, common.HexToAddress("01"), big.NewInt(1), 10000, big.NewInt(10), &feeCurrencyAddr, &gatewayFeeRecipientAddr, big.NewInt(34), []byte{04})
-	return types.NewBlock(header, []*types.Transaction{tx}, nil, nil)
+	tx2 := types.NewTransaction(2, common.HexToAddress("01"), big.NewInt(1), 10000, big.NewInt(10), &feeCurrencyAddr, &gatewayFeeRecipientAddr, big.NewInt(34), []byte{05})
+	return types.NewBlock(header, []*types.Transaction{tx, tx2}, nil, nil)
 }
 
 func (s *ListenerTestSuite) TestListenerStartStop() {
@@ -102,10 +107,13 @@ func (s *ListenerTestSuite) TestLatestBlockUpdate() {
 	l := NewListener(cfg, s.clientMock, s.blockStorerMock, stopChn, errChn, s.routerMock, s.validatorsAggregatorMock)
 
 	s.clientMock.EXPECT().LatestBlock().Return(big.NewInt(555), nil)
+	header := &types.Header{Number: big.NewInt(1)}
+	s.clientMock.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(1)).Return(header, nil)
+	s.blockStorerMock.EXPECT().BlockHash(gomock.Any()).Return(common.Hash{}, blockdb.ErrBlockNotInHistory)
 	//No event logs found
 	s.clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return(make([]types.Log, 0), nil)
 
-	s.blockStorerMock.EXPECT().StoreBlock(big.NewInt(1))
+	s.blockStorerMock.EXPECT().StoreBlock(big.NewInt(1), header.Hash())
 
 	//ON second call to latest block we stopping goroutine
 	s.clientMock.EXPECT().LatestBlock().DoAndReturn(func() (*big.Int, error) { close(stopChn); return nil, errors.New("err") })
@@ -114,6 +122,38 @@ func (s *ListenerTestSuite) TestLatestBlockUpdate() {
 	s.Equal(cfg.StartBlock.String(), "2")
 }
 
+func (s *ListenerTestSuite) TestPollBlocksStopsOnParentMismatch() {
+	stopChn := make(chan struct{})
+	errChn := make(chan error, 1)
+	cfg := &config.CeloChainConfig{StartBlock: big.NewInt(5), BridgeContract: common.Address{}}
+	l := NewListener(cfg, s.clientMock, s.blockStorerMock, stopChn, errChn, s.routerMock, s.validatorsAggregatorMock)
+
+	s.clientMock.EXPECT().LatestBlock().Return(big.NewInt(555), nil)
+	header := &types.Header{Number: big.NewInt(5), ParentHash: common.Hash{1}}
+	s.clientMock.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(5)).Return(header, nil)
+	s.blockStorerMock.EXPECT().BlockHash(big.NewInt(4)).Return(common.Hash{2}, nil)
+
+	s.Nil(l.pollBlocks())
+	s.True(errors.Is(<-errChn, ErrChainMismatch))
+	// Block is not processed
+	s.Equal(cfg.StartBlock.String(), "5")
+}
+
+func (s *ListenerTestSuite) TestStartPollingBlocksChecksProcessedBlock() {
+	cfg := &config.CeloChainConfig{StartBlock: big.NewInt(5)}
+	l := NewListener(cfg, s.clientMock, s.blockStorerMock, make(chan struct{}), make(chan error), s.routerMock, s.validatorsAggregatorMock)
+	header := &types.Header{Number: big.NewInt(5)}
+	s.blockStorerMock.EXPECT().BlockHash(big.NewInt(5)).Return(common.Hash{1}, nil)
+	s.clientMock.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(5)).Return(header, nil)
+
+	err := l.StartPollingBlocks()
+	s.True(errors.Is(err, ErrChainMismatch))
+
+	// Start block that was never processed is not checked
+	s.blockStorerMock.EXPECT().BlockHash(big.NewInt(5)).Return(common.Hash{}, blockdb.ErrBlockNotInHistory)
+	s.Nil(l.checkProcessedBlock(cfg.StartBlock))
+}
+
 func (s *ListenerTestSuite) TestHandleErc20DepositedEventSucccess() {
 
 	stopChn := make(chan struct{})
@@ -317,7 +357,7 @@ func (s *ListenerTestSuite) TestGetDepositEventsAndProofsForBlockerERC20() {
 
 	destID := utils.ChainId(logs[0].Topics[1].Big().Uint64())
 	pk := []byte{0x1f}
-	s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte{0x1f}, nil)
+	s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte{0x1f}, nil)
 
 	// replace block with customized block to hold IstanbulExtra data
 	block := dummyBlockWithIstanbulExtra(123)
@@ -437,7 +477,7 @@ func (s *ListenerTestSuite) TestGetDepositEventsAndProofsForBlockerERC721() {
 
 	destID := utils.ChainId(logs[0].Topics[1].Big().Uint64())
 	pk := []byte{0x1f}
-	s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), gomock.Any(), gomock.Any()).Return(pk, nil)
+	s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(pk, nil)
 
 	// replace block with customized block to hold IstanbulExtra data
 	block := dummyBlockWithIstanbulExtra(123)
@@ -532,7 +572,7 @@ func (s *ListenerTestSuite) TestGetDepositEventsAndProofsForBlockerGeneric() {
 
 	destID := utils.ChainId(logs[0].Topics[1].Big().Uint64())
 	pk := []byte{0x1f}
-	s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), gomock.Any(), gomock.Any()).Return(pk, nil)
+	s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(pk, nil)
 
 	// replace block with customized block to hold IstanbulExtra data
 	block := dummyBlockWithIstanbulExtra(123)
@@ -676,3 +716,292 @@ func (s *ListenerTestSuite) TestBuildQuery() {
 	s.Equal(expected, actual)
 
 }
+
+func (s *ListenerTestSuite) TestFetchDeposit() {
+	address := common.HexToAddress("0x71C7656EC7ab88b098defB751B7401B5f6d8976F")
+	cfg := &config.CeloChainConfig{
+		ID:                   2,
+		Erc20HandlerContract: address,
+		StartBlock:           big.NewInt(1),
+		BridgeContract:       address,
+	}
+	listener := NewListener(cfg, s.clientMock, s.blockStorerMock, make(chan struct{}), make(chan error), s.routerMock, s.validatorsAggregatorMock)
+	listener.SetContracts(s.bridge, s.erc20Handler, s.erc721Handler, s.genericHandler)
+
+	depositLog := types.Log{
+		Topics: []common.Hash{
+			utils.Deposit.GetTopic(),
+			common.BigToHash(big.NewInt(1)),
+			address.Hash(),
+			common.BigToHash(big.NewInt(7)),
+		},
+		BlockNumber: 123,
+		TxIndex:     1,
+	}
+	s.clientMock.EXPECT().LatestBlock().Return(big.NewInt(15000), nil)
+	// Deposit is searched backwards range by range
+	s.clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, q eth.FilterQuery) ([]types.Log, error) {
+		s.Equal(big.NewInt(5000), q.FromBlock)
+		s.Equal(big.NewInt(15000), q.ToBlock)
+		s.Equal([]common.Hash{common.BigToHash(big.NewInt(7)), common.BigToHash(big.NewInt(6))}, q.Topics[3])
+		return []types.Log{}, nil
+	})
+	s.clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, q eth.FilterQuery) ([]types.Log, error) {
+		s.Equal(big.NewInt(0), q.FromBlock)
+		s.Equal(big.NewInt(4999), q.ToBlock)
+		return []types.Log{depositLog}, nil
+	})
+	s.clientMock.EXPECT().BlockByNumber(gomock.Any(), big.NewInt(123)).Return(dummyBlockWithIstanbulExtra(123), nil)
+	s.bridge.EXPECT().ResourceIDToHandlerAddress(gomock.Any(), [32]byte(address.Hash())).Return(address, nil)
+	s.erc20Handler.EXPECT().GetDepositRecord(gomock.Any(), uint64(7), uint8(1)).Return(ERC20Handler.ERC20HandlerDepositRecord{Amount: big.NewInt(10), DestinationRecipientAddress: []byte{1}}, nil)
+	s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), big.NewInt(123), uint8(2), gomock.Any()).Return([]byte{0x1f}, nil)
+
+	m, err := listener.FetchDeposit(1, 7)
+	s.Nil(err)
+	s.Equal(utils.ChainId(2), m.Source)
+	s.Equal(utils.ChainId(1), m.Destination)
+	s.Equal(utils.Nonce(7), m.DepositNonce)
+	s.NotNil(m.MPParams)
+	s.Equal([]byte{0x1f}, m.SVParams.AggregatePublicKey)
+}
+
+func (s *ListenerTestSuite) TestFetchDepositNotFound() {
+	cfg := &config.CeloChainConfig{ID: 2, StartBlock: big.NewInt(1)}
+	listener := NewListener(cfg, s.clientMock, s.blockStorerMock, make(chan struct{}), make(chan error), s.routerMock, s.validatorsAggregatorMock)
+	s.clientMock.EXPECT().LatestBlock().Return(big.NewInt(100), nil)
+	s.clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{}, nil)
+	_, err := listener.FetchDeposit(1, 7)
+	s.Equal(ErrDepositNotFound, err)
+}
+
+func (s *ListenerTestSuite) TestFetchDepositStopsAtPreviousDeposit() {
+	cfg := &config.CeloChainConfig{ID: 2, StartBlock: big.NewInt(1)}
+	listener := NewListener(cfg, s.clientMock, s.blockStorerMock, make(chan struct{}), make(chan error), s.routerMock, s.validatorsAggregatorMock)
+	previous := types.Log{
+		Topics: []common.Hash{
+			utils.Deposit.GetTopic(),
+			common.BigToHash(big.NewInt(1)),
+			{},
+			common.BigToHash(big.NewInt(6)),
+		},
+		BlockNumber: 9000,
+	}
+	s.clientMock.EXPECT().LatestBlock().Return(big.NewInt(15000), nil)
+	// Older ranges are not searched once previous deposit is found
+	s.clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{previous}, nil).Times(1)
+	_, err := listener.FetchDeposit(1, 7)
+	s.Equal(ErrDepositNotFound, err)
+}
+
+func (s *ListenerTestSuite) TestFetchDepositsInRange() {
+	address := common.HexToAddress("0x71C7656EC7ab88b098defB751B7401B5f6d8976F")
+	cfg := &config.CeloChainConfig{
+		ID:                   2,
+		Erc20HandlerContract: address,
+		StartBlock:           big.NewInt(1),
+		BridgeContract:       address,
+	}
+	listener := NewListener(cfg, s.clientMock, s.blockStorerMock, make(chan struct{}), make(chan error), s.routerMock, s.validatorsAggregatorMock)
+	listener.SetContracts(s.bridge, s.erc20Handler, s.erc721Handler, s.genericHandler)
+
+	depositLog := func(nonce int64, txIndex uint) types.Log {
+		return types.Log{
+			Topics: []common.Hash{
+				utils.Deposit.GetTopic(),
+				common.BigToHash(big.NewInt(1)),
+				address.Hash(),
+				common.BigToHash(big.NewInt(nonce)),
+			},
+			BlockNumber: 123,
+			TxIndex:     txIndex,
+		}
+	}
+	// Range is queried forwards in chunks of DepositSearchRange blocks
+	s.clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, q eth.FilterQuery) ([]types.Log, error) {
+		s.Equal(big.NewInt(100), q.FromBlock)
+		s.Equal(big.NewInt(10099), q.ToBlock)
+		s.Len(q.Topics, 1)
+		return []types.Log{depositLog(7, 0), depositLog(8, 1)}, nil
+	})
+	s.clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, q eth.FilterQuery) ([]types.Log, error) {
+		s.Equal(big.NewInt(10100), q.FromBlock)
+		s.Equal(big.NewInt(12000), q.ToBlock)
+		return []types.Log{}, nil
+	})
+	s.clientMock.EXPECT().BlockByNumber(gomock.Any(), big.NewInt(123)).Return(dummyBlockWithIstanbulExtra(123), nil).Times(1)
+	s.bridge.EXPECT().ResourceIDToHandlerAddress(gomock.Any(), [32]byte(address.Hash())).Return(address, nil).Times(2)
+	s.erc20Handler.EXPECT().GetDepositRecord(gomock.Any(), uint64(7), uint8(1)).Return(ERC20Handler.ERC20HandlerDepositRecord{Amount: big.NewInt(10), DestinationRecipientAddress: []byte{1}}, nil)
+	s.erc20Handler.EXPECT().GetDepositRecord(gomock.Any(), uint64(8), uint8(1)).Return(ERC20Handler.ERC20HandlerDepositRecord{Amount: big.NewInt(20), DestinationRecipientAddress: []byte{1}}, nil)
+	s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), big.NewInt(123), uint8(2), gomock.Any()).Return([]byte{0x1f}, nil).Times(2)
+
+	msgs, err := listener.FetchDepositsInRange(big.NewInt(100), big.NewInt(12000), nil)
+	s.Nil(err)
+	s.Len(msgs, 2)
+	s.Equal(utils.Nonce(7), msgs[0].DepositNonce)
+	s.Equal(utils.Nonce(8), msgs[1].DepositNonce)
+}
+
+func (s *ListenerTestSuite) TestFetchDepositsInRangeWithNonce() {
+	cfg := &config.CeloChainConfig{ID: 2, StartBlock: big.NewInt(1)}
+	listener := NewListener(cfg, s.clientMock, s.blockStorerMock, make(chan struct{}), make(chan error), s.routerMock, s.validatorsAggregatorMock)
+	nonce := utils.Nonce(7)
+	s.clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, q eth.FilterQuery) ([]types.Log, error) {
+		s.Equal(common.BigToHash(big.NewInt(7)), q.Topics[3][0])
+		return []types.Log{}, nil
+	})
+	msgs, err := listener.FetchDepositsInRange(big.NewInt(100), big.NewInt(200), &nonce)
+	s.Nil(err)
+	s.Len(msgs, 0)
+}
+
+func (s *ListenerTestSuite) TestUnrecognizedHandlerRecordedAsDeadLetter() {
+	address := common.HexToAddress("0x71C7656EC7ab88b098defB751B7401B5f6d8976F")
+	unknownHandler := common.HexToAddress("0x67C7656EC7ab88b098defB751B7401B5f6d8976F")
+	cfg := &config.CeloChainConfig{
+		ID:                   2,
+		Erc20HandlerContract: address,
+		StartBlock:           big.NewInt(1),
+		BridgeContract:       address,
+	}
+	listener := NewListener(cfg, s.clientMock, s.blockStorerMock, make(chan struct{}), make(chan error), s.routerMock, s.validatorsAggregatorMock)
+	listener.SetContracts(s.bridge, s.erc20Handler, s.erc721Handler, s.genericHandler)
+	listener.SetDeadLetters(s.deadLettersMock)
+
+	logs := []types.Log{
+		{
+			Topics:      []common.Hash{utils.Deposit.GetTopic(), common.BigToHash(big.NewInt(1)), unknownHandler.Hash(), common.BigToHash(big.NewInt(7))},
+			BlockNumber: 123,
+			TxHash:      common.Hash{7},
+		},
+		{
+			Topics:      []common.Hash{utils.Deposit.GetTopic(), common.BigToHash(big.NewInt(1)), address.Hash(), common.BigToHash(big.NewInt(8))},
+			BlockNumber: 123,
+			TxIndex:     1,
+		},
+	}
+	s.clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return(logs, nil)
+	s.clientMock.EXPECT().BlockByNumber(gomock.Any(), big.NewInt(123)).Return(dummyBlockWithIstanbulExtra(123), nil)
+	s.bridge.EXPECT().ResourceIDToHandlerAddress(gomock.Any(), [32]byte(unknownHandler.Hash())).Return(unknownHandler, nil)
+	s.bridge.EXPECT().ResourceIDToHandlerAddress(gomock.Any(), [32]byte(address.Hash())).Return(address, nil)
+	s.erc20Handler.EXPECT().GetDepositRecord(gomock.Any(), uint64(8), uint8(1)).Return(ERC20Handler.ERC20HandlerDepositRecord{Amount: big.NewInt(10), DestinationRecipientAddress: []byte{1}}, nil)
+	s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), big.NewInt(123), uint8(2), gomock.Any()).Return([]byte{0x1f}, nil)
+	s.deadLettersMock.EXPECT().Record(gomock.Any()).DoAndReturn(func(dl *deadletter.DeadLetter) error {
+		s.Equal(utils.ChainId(2), dl.Source)
+		s.Equal(utils.ChainId(1), dl.Destination)
+		s.Equal(utils.Nonce(7), dl.Nonce)
+		s.Equal(uint64(123), dl.Block)
+		s.Equal(common.Hash{7}, dl.TxHash)
+		s.Contains(dl.Reason, unknownHandler.Hex())
+		s.Nil(dl.Message)
+		return nil
+	})
+	// Deposits after dead letter in the same block are still routed
+	s.routerMock.EXPECT().Send(gomock.Any()).DoAndReturn(func(m *utils.Message) error {
+		s.Equal(utils.Nonce(8), m.DepositNonce)
+		return nil
+	})
+
+	s.Nil(listener.getDepositEventsAndProofsForBlock(big.NewInt(123)))
+}
+
+func (s *ListenerTestSuite) TestRouteFailureRecordedAsDeadLetter() {
+	address := common.HexToAddress("0x71C7656EC7ab88b098defB751B7401B5f6d8976F")
+	cfg := &config.CeloChainConfig{
+		ID:                   2,
+		Erc20HandlerContract: address,
+		StartBlock:           big.NewInt(1),
+		BridgeContract:       address,
+	}
+	listener := NewListener(cfg, s.clientMock, s.blockStorerMock, make(chan struct{}), make(chan error), s.routerMock, s.validatorsAggregatorMock)
+	listener.SetContracts(s.bridge, s.erc20Handler, s.erc721Handler, s.genericHandler)
+	listener.SetDeadLetters(s.deadLettersMock)
+
+	logs := []types.Log{{
+		Topics:      []common.Hash{utils.Deposit.GetTopic(), common.BigToHash(big.NewInt(9)), address.Hash(), common.BigToHash(big.NewInt(7))},
+		BlockNumber: 123,
+	}}
+	s.clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return(logs, nil)
+	s.clientMock.EXPECT().BlockByNumber(gomock.Any(), big.NewInt(123)).Return(dummyBlockWithIstanbulExtra(123), nil)
+	s.bridge.EXPECT().ResourceIDToHandlerAddress(gomock.Any(), [32]byte(address.Hash())).Return(address, nil)
+	s.erc20Handler.EXPECT().GetDepositRecord(gomock.Any(), uint64(7), uint8(9)).Return(ERC20Handler.ERC20HandlerDepositRecord{Amount: big.NewInt(10), DestinationRecipientAddress: []byte{1}}, nil)
+	s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), big.NewInt(123), uint8(2), gomock.Any()).Return([]byte{0x1f}, nil)
+	s.routerMock.EXPECT().Send(gomock.Any()).Return(errors.New("unknown destination chainId: 9"))
+	s.deadLettersMock.EXPECT().Record(gomock.Any()).DoAndReturn(func(dl *deadletter.DeadLetter) error {
+		s.Equal(utils.ChainId(9), dl.Destination)
+		s.Equal("unknown destination chainId: 9", dl.Reason)
+		s.NotNil(dl.Message)
+		return nil
+	})
+
+	s.Nil(listener.getDepositEventsAndProofsForBlock(big.NewInt(123)))
+}
+
+func (s *ListenerTestSuite) TestRetryDeadLetter() {
+	address := common.HexToAddress("0x71C7656EC7ab88b098defB751B7401B5f6d8976F")
+	cfg := &config.CeloChainConfig{
+		ID:                   2,
+		Erc20HandlerContract: address,
+		StartBlock:           big.NewInt(1),
+		BridgeContract:       address,
+	}
+	listener := NewListener(cfg, s.clientMock, s.blockStorerMock, make(chan struct{}), make(chan error), s.routerMock, s.validatorsAggregatorMock)
+	listener.SetContracts(s.bridge, s.erc20Handler, s.erc721Handler, s.genericHandler)
+
+	depositLog := types.Log{
+		Topics:      []common.Hash{utils.Deposit.GetTopic(), common.BigToHash(big.NewInt(1)), address.Hash(), common.BigToHash(big.NewInt(7))},
+		BlockNumber: 123,
+	}
+	s.clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, q eth.FilterQuery) ([]types.Log, error) {
+		s.Equal(big.NewInt(123), q.FromBlock)
+		s.Equal(big.NewInt(123), q.ToBlock)
+		s.Equal(common.BigToHash(big.NewInt(1)), q.Topics[1][0])
+		s.Equal(common.BigToHash(big.NewInt(7)), q.Topics[3][0])
+		return []types.Log{depositLog}, nil
+	})
+	s.clientMock.EXPECT().BlockByNumber(gomock.Any(), big.NewInt(123)).Return(dummyBlockWithIstanbulExtra(123), nil)
+	s.bridge.EXPECT().ResourceIDToHandlerAddress(gomock.Any(), [32]byte(address.Hash())).Return(address, nil)
+	s.erc20Handler.EXPECT().GetDepositRecord(gomock.Any(), uint64(7), uint8(1)).Return(ERC20Handler.ERC20HandlerDepositRecord{Amount: big.NewInt(10), DestinationRecipientAddress: []byte{1}}, nil)
+	s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), big.NewInt(123), uint8(2), gomock.Any()).Return([]byte{0x1f}, nil)
+	s.routerMock.EXPECT().Send(gomock.Any()).Return(nil)
+
+	s.Nil(listener.RetryDeadLetter(&deadletter.DeadLetter{Source: 2, Destination: 1, Nonce: 7, Block: 123}))
+}
+
+func (s *ListenerTestSuite) TestRetriedBlockReusesCachedTrie() {
+	address := common.HexToAddress("0x71C7656EC7ab88b098defB751B7401B5f6d8976F")
+	cfg := &config.CeloChainConfig{
+		ID:                   2,
+		Erc20HandlerContract: address,
+		StartBlock:           big.NewInt(1),
+		BridgeContract:       address,
+	}
+	listener := NewListener(cfg, s.clientMock, s.blockStorerMock, make(chan struct{}), make(chan error), s.routerMock, s.validatorsAggregatorMock)
+	listener.SetContracts(s.bridge, s.erc20Handler, s.erc721Handler, s.genericHandler)
+
+	depositLog := types.Log{
+		Topics:      []common.Hash{utils.Deposit.GetTopic(), common.BigToHash(big.NewInt(1)), address.Hash(), common.BigToHash(big.NewInt(7))},
+		BlockNumber: 123,
+		TxIndex:     1,
+	}
+	block := dummyBlockWithIstanbulExtra(123)
+
+	s.clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{depositLog}, nil).Times(2)
+	s.clientMock.EXPECT().BlockByNumber(gomock.Any(), big.NewInt(123)).Return(block, nil).Times(2)
+	s.bridge.EXPECT().ResourceIDToHandlerAddress(gomock.Any(), [32]byte(address.Hash())).Return(address, nil).Times(2)
+	s.erc20Handler.EXPECT().GetDepositRecord(gomock.Any(), uint64(7), uint8(1)).Return(ERC20Handler.ERC20HandlerDepositRecord{Amount: big.NewInt(10), DestinationRecipientAddress: []byte{1}}, nil).Times(2)
+	gomock.InOrder(
+		s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), big.NewInt(123), uint8(2), gomock.Any()).Return(nil, errors.New("validators not synced")),
+		s.validatorsAggregatorMock.EXPECT().GetAPKForBlock(gomock.Any(), big.NewInt(123), uint8(2), gomock.Any()).Return([]byte{0x1f}, nil),
+	)
+	s.routerMock.EXPECT().Send(gomock.Any()).Return(nil)
+
+	s.NotNil(listener.getDepositEventsAndProofsForBlock(big.NewInt(123)))
+	trie, ok := listener.tries.Get(block.Hash())
+	s.True(ok)
+	s.Nil(listener.getDepositEventsAndProofsForBlock(big.NewInt(123)))
+	// Retry proves deposit with trie built by the failed attempt
+	cached, ok := listener.tries.Get(block.Hash())
+	s.True(ok)
+	s.Same(trie, cached)
+	s.Equal(1, listener.tries.Len())
+}
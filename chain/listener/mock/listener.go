@@ -5,10 +5,13 @@
 package mock_listener
 
 import (
+	context "context"
 	big "math/big"
 	reflect "reflect"
 
+	deadletter "github.com/ChainSafe/chainbridge-celo/deadletter"
 	utils "github.com/ChainSafe/chainbridge-celo/utils"
+	common "github.com/ethereum/go-ethereum/common"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -73,17 +76,69 @@ func (m *MockBlockstorer) EXPECT() *MockBlockstorerMockRecorder {
 }
 
 // StoreBlock mocks base method.
-func (m *MockBlockstorer) StoreBlock(arg0 *big.Int) error {
+func (m *MockBlockstorer) StoreBlock(block *big.Int, hash common.Hash) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StoreBlock", arg0)
+	ret := m.ctrl.Call(m, "StoreBlock", block, hash)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StoreBlock indicates an expected call of StoreBlock.
-func (mr *MockBlockstorerMockRecorder) StoreBlock(arg0 interface{}) *gomock.Call {
+func (mr *MockBlockstorerMockRecorder) StoreBlock(block, hash interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBlock", reflect.TypeOf((*MockBlockstorer)(nil).StoreBlock), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBlock", reflect.TypeOf((*MockBlockstorer)(nil).StoreBlock), block, hash)
+}
+
+// BlockHash mocks base method.
+func (m *MockBlockstorer) BlockHash(block *big.Int) (common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockHash", block)
+	ret0, _ := ret[0].(common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockHash indicates an expected call of BlockHash.
+func (mr *MockBlockstorerMockRecorder) BlockHash(block interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockHash", reflect.TypeOf((*MockBlockstorer)(nil).BlockHash), block)
+}
+
+// MockDeadLetterRecorder is a mock of DeadLetterRecorder interface.
+type MockDeadLetterRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterRecorderMockRecorder
+}
+
+// MockDeadLetterRecorderMockRecorder is the mock recorder for MockDeadLetterRecorder.
+type MockDeadLetterRecorderMockRecorder struct {
+	mock *MockDeadLetterRecorder
+}
+
+// NewMockDeadLetterRecorder creates a new mock instance.
+func NewMockDeadLetterRecorder(ctrl *gomock.Controller) *MockDeadLetterRecorder {
+	mock := &MockDeadLetterRecorder{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterRecorder) EXPECT() *MockDeadLetterRecorderMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockDeadLetterRecorder) Record(dl *deadletter.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", dl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockDeadLetterRecorderMockRecorder) Record(dl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockDeadLetterRecorder)(nil).Record), dl)
 }
 
 // MockValidatorsAggregator is a mock of ValidatorsAggregator interface.
@@ -110,16 +165,16 @@ func (m *MockValidatorsAggregator) EXPECT() *MockValidatorsAggregatorMockRecorde
 }
 
 // GetAPKForBlock mocks base method.
-func (m *MockValidatorsAggregator) GetAPKForBlock(block *big.Int, chainID uint8, epochSize uint64) ([]byte, error) {
+func (m *MockValidatorsAggregator) GetAPKForBlock(ctx context.Context, block *big.Int, chainID uint8, epochSize uint64) ([]byte, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAPKForBlock", block, chainID, epochSize)
+	ret := m.ctrl.Call(m, "GetAPKForBlock", ctx, block, chainID, epochSize)
 	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAPKForBlock indicates an expected call of GetAPKForBlock.
-func (mr *MockValidatorsAggregatorMockRecorder) GetAPKForBlock(block, chainID, epochSize interface{}) *gomock.Call {
+func (mr *MockValidatorsAggregatorMockRecorder) GetAPKForBlock(ctx, block, chainID, epochSize interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPKForBlock", reflect.TypeOf((*MockValidatorsAggregator)(nil).GetAPKForBlock), block, chainID, epochSize)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPKForBlock", reflect.TypeOf((*MockValidatorsAggregator)(nil).GetAPKForBlock), ctx, block, chainID, epochSize)
 }
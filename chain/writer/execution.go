@@ -0,0 +1,172 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package writer
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/proposalstore"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+)
+
+// Time between scans for passed but not executed proposals
+var ExecutionSweepInterval = time.Minute * 10
+
+// Number of blocks scanned for passed proposals by a single logs query
+var ExecutionSweepRange = big.NewInt(10000)
+
+type passedProposal struct {
+	dataHash ethcommon.Hash
+	block    *big.Int // block proposal passed in
+}
+
+// StartExecutionSweeper periodically scans bridge for proposals that passed but were never executed and executes them
+func (w *writer) StartExecutionSweeper() {
+	go func() {
+		ticker := time.NewTicker(ExecutionSweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				w.sweepPassedProposals()
+			}
+		}
+	}()
+}
+
+func (w *writer) sweepPassedProposals() {
+	latestBlock, err := w.client.LatestBlock()
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to fetch latest block")
+		return
+	}
+	err = w.scanProposalEvents(latestBlock)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to scan proposal events")
+		return
+	}
+
+	var recovered, failed int
+	for key, p := range w.passed {
+		// Proposals that passed recently are still watched by watchThenExecute
+		if new(big.Int).Sub(latestBlock, p.block).Cmp(big.NewInt(ExecuteBlockWatchLimit)) <= 0 {
+			continue
+		}
+		if !w.proposalIsPassed(key.source, key.nonce, p.dataHash) {
+			w.removePassed(key)
+			continue
+		}
+		if w.recoverExecution(key, p) {
+			log.Info().Interface("src", key.source).Interface("nonce", key.nonce).Str("dataHash", p.dataHash.Hex()).Str("passedBlock", p.block.String()).Msg("Recovered execution of passed proposal")
+			w.removePassed(key)
+			recovered++
+		} else {
+			failed++
+		}
+	}
+	if recovered > 0 || failed > 0 {
+		log.Info().Interface("chain", w.cfg.ID).Int("recovered", recovered).Int("failed", failed).Msg("Execution sweep finished")
+	}
+}
+
+// scanProposalEvents updates passed proposals with ProposalEvent logs up to latestBlock. Chain that was never scanned is
+// scanned from sweep start block, or start block of the chain if it is not set. Progress is persisted after every range
+func (w *writer) scanProposalEvents(latestBlock *big.Int) error {
+	from := new(big.Int).Set(w.cfg.StartBlock)
+	if w.sweptBlock != nil {
+		from = new(big.Int).Add(w.sweptBlock, big.NewInt(1))
+	} else if w.sweepStart != nil {
+		from = new(big.Int).Set(w.sweepStart)
+	}
+	for from.Cmp(latestBlock) <= 0 {
+		to := new(big.Int).Add(from, ExecutionSweepRange)
+		to.Sub(to, big.NewInt(1))
+		if to.Cmp(latestBlock) > 0 {
+			to.Set(latestBlock)
+		}
+		query := buildQuery(w.cfg.BridgeContract, utils.ProposalEvent, from, to)
+		evts, err := w.client.FilterLogs(context.Background(), query)
+		if err != nil {
+			return err
+		}
+		for _, evt := range evts {
+			if len(evt.Topics) != 4 || len(evt.Data) < 64 {
+				continue
+			}
+			key := proposalKey{
+				source: utils.ChainId(evt.Topics[1].Big().Uint64()),
+				nonce:  utils.Nonce(evt.Topics[2].Big().Uint64()),
+			}
+			switch uint8(evt.Topics[3].Big().Uint64()) {
+			case ProposalStatusPassed:
+				w.addPassed(key, &passedProposal{
+					dataHash: ethcommon.BytesToHash(evt.Data[32:64]),
+					block:    new(big.Int).SetUint64(evt.BlockNumber),
+				})
+			case ProposalStatusTransferred, ProposalStatusCancelled:
+				w.removePassed(key)
+			}
+		}
+		w.sweptBlock = to
+		if w.proposals != nil {
+			err = w.proposals.SetSweptBlock(to)
+			if err != nil {
+				return err
+			}
+		}
+		from = new(big.Int).Add(to, big.NewInt(1))
+	}
+	return nil
+}
+
+func (w *writer) addPassed(key proposalKey, p *passedProposal) {
+	w.passed[key] = p
+	if w.proposals != nil {
+		err := w.proposals.PutPassed(&proposalstore.PassedProposal{Source: key.source, Nonce: key.nonce, DataHash: p.dataHash, Block: p.block.Uint64()})
+		if err != nil {
+			log.Error().Err(err).Interface("src", key.source).Interface("nonce", key.nonce).Msg("Failed to persist passed proposal")
+		}
+	}
+}
+
+func (w *writer) removePassed(key proposalKey) {
+	delete(w.passed, key)
+	if w.proposals != nil {
+		err := w.proposals.DeletePassed(key.source, key.nonce)
+		if err != nil {
+			log.Error().Err(err).Interface("src", key.source).Interface("nonce", key.nonce).Msg("Failed to remove passed proposal")
+		}
+	}
+}
+
+// recoverExecution rebuilds execution data of passed proposal from the source deposit and executes it
+func (w *writer) recoverExecution(key proposalKey, p *passedProposal) bool {
+	fetcher, ok := w.fetchers[key.source]
+	if !ok {
+		log.Warn().Interface("src", key.source).Interface("nonce", key.nonce).Msg("Passed proposal is not executed, source chain is unknown")
+		return false
+	}
+	m, err := fetcher.FetchDeposit(w.cfg.ID, key.nonce)
+	if err != nil {
+		log.Error().Err(err).Interface("src", key.source).Interface("nonce", key.nonce).Msg("Failed to fetch deposit of passed proposal")
+		return false
+	}
+	data, handlerContract, err := w.proposalData(m)
+	if err != nil {
+		log.Error().Err(err).Interface("src", key.source).Interface("nonce", key.nonce).Msg("Failed to create proposal data")
+		return false
+	}
+	dataHash := CreateProposalDataHash(data, handlerContract, m.MPParams, m.SVParams)
+	if dataHash != p.dataHash {
+		log.Error().Bool("alert", true).Interface("src", key.source).Interface("nonce", key.nonce).Str("expected", p.dataHash.Hex()).Str("rebuilt", dataHash.Hex()).Msg("Rebuilt proposal data does not match passed proposal")
+		return false
+	}
+	return w.submitExecution(m, data, dataHash) == nil
+}
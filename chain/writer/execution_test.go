@@ -0,0 +1,141 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+package writer
+
+import (
+	"math/big"
+	"os"
+
+	"github.com/ChainSafe/chainbridge-celo/bindings/Bridge"
+	"github.com/ChainSafe/chainbridge-celo/chain/config"
+	mock_writer "github.com/ChainSafe/chainbridge-celo/chain/writer/mock"
+	"github.com/ChainSafe/chainbridge-celo/proposalstore"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/mock/gomock"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+func proposalEventLog(block uint64, source uint8, nonce uint64, status uint8, dataHash common.Hash) types.Log {
+	data := make([]byte, 64)
+	copy(data[32:], dataHash.Bytes())
+	return types.Log{
+		Topics: []common.Hash{
+			utils.ProposalEvent.GetTopic(),
+			common.BigToHash(big.NewInt(int64(source))),
+			common.BigToHash(new(big.Int).SetUint64(nonce)),
+			common.BigToHash(big.NewInt(int64(status))),
+		},
+		Data:        data,
+		BlockNumber: block,
+	}
+}
+
+func (s *WriterTestSuite) newSweepWriter() (*writer, *mock_writer.MockDepositFetcher, *utils.Message, common.Hash) {
+	cfg := &config.CeloChainConfig{ID: 2, StartBlock: big.NewInt(1), BridgeContract: common.Address{}}
+	w := NewWriter(s.client, cfg, make(chan struct{}), make(chan error), nil)
+	w.SetBridge(s.bridgeMock)
+	fetcher := mock_writer.NewMockDepositFetcher(s.gomockController)
+	w.SetDepositFetcher(1, fetcher)
+	m := utils.NewFungibleTransfer(1, 2, utils.Nonce(5), [32]byte{1}, &utils.MerkleProof{}, &utils.SignatureVerification{}, big.NewInt(10), make([]byte, 32))
+	data, handler, err := w.proposalData(m)
+	s.Nil(err)
+	return w, fetcher, m, CreateProposalDataHash(data, handler, m.MPParams, m.SVParams)
+}
+
+func (s *WriterTestSuite) TestSweepExecutesPassedProposal() {
+	w, fetcher, m, dataHash := s.newSweepWriter()
+	s.client.EXPECT().CallOpts().Return(nil).AnyTimes()
+	s.client.EXPECT().Opts().Return(&bind.TransactOpts{}).AnyTimes()
+	s.client.EXPECT().LatestBlock().Return(big.NewInt(200), nil)
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{
+		proposalEventLog(10, 1, 5, ProposalStatusPassed, dataHash),
+		// Passed and executed proposal should be skipped
+		proposalEventLog(10, 1, 6, ProposalStatusPassed, common.Hash{6}),
+		proposalEventLog(11, 1, 6, ProposalStatusTransferred, common.Hash{6}),
+	}, nil)
+	s.bridgeMock.EXPECT().GetProposal(gomock.Any(), uint8(1), uint64(5), [32]byte(dataHash)).Return(Bridge.BridgeProposal{Status: ProposalStatusPassed}, nil)
+	fetcher.EXPECT().FetchDeposit(utils.ChainId(2), utils.Nonce(5)).Return(m, nil)
+	s.client.EXPECT().LockAndUpdateOpts().Return(nil)
+	s.bridgeMock.EXPECT().ExecuteProposal(gomock.Any(), uint8(1), uint64(5), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil, nil, nil, nil), nil)
+	s.client.EXPECT().UnlockOpts()
+
+	w.sweepPassedProposals()
+	s.Equal(0, len(w.passed))
+	s.Equal(big.NewInt(200), w.sweptBlock)
+}
+
+func (s *WriterTestSuite) TestSweepSkipsRecentlyPassedProposal() {
+	w, _, _, dataHash := s.newSweepWriter()
+	s.client.EXPECT().LatestBlock().Return(big.NewInt(50), nil)
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{proposalEventLog(10, 1, 5, ProposalStatusPassed, dataHash)}, nil)
+
+	// Proposal passed less than ExecuteBlockWatchLimit blocks ago is still watched by watchThenExecute
+	w.sweepPassedProposals()
+	s.Equal(1, len(w.passed))
+}
+
+func (s *WriterTestSuite) TestSweepRebuiltDataHashMismatch() {
+	w, fetcher, m, _ := s.newSweepWriter()
+	s.client.EXPECT().CallOpts().Return(nil).AnyTimes()
+	s.client.EXPECT().LatestBlock().Return(big.NewInt(200), nil)
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{proposalEventLog(10, 1, 5, ProposalStatusPassed, common.Hash{9})}, nil)
+	s.bridgeMock.EXPECT().GetProposal(gomock.Any(), uint8(1), uint64(5), gomock.Any()).Return(Bridge.BridgeProposal{Status: ProposalStatusPassed}, nil)
+	fetcher.EXPECT().FetchDeposit(utils.ChainId(2), utils.Nonce(5)).Return(m, nil)
+
+	// ExecuteProposal should not be called with data that does not match passed proposal
+	w.sweepPassedProposals()
+	s.Equal(1, len(w.passed))
+}
+
+func (s *WriterTestSuite) TestSweepResumesFromPersistedBlockAfterRestart() {
+	db, err := leveldb.OpenFile("./test/db", nil)
+	s.Nil(err)
+	defer os.RemoveAll("./test")
+	defer db.Close()
+	store := proposalstore.NewStore(db, 2)
+	defer func(r *big.Int) { ExecutionSweepRange = r }(ExecutionSweepRange)
+	ExecutionSweepRange = big.NewInt(100)
+
+	w, _, _, dataHash := s.newSweepWriter()
+	s.Nil(w.SetProposalStore(store))
+	w.SetExecutionSweepStart(big.NewInt(20))
+	s.client.EXPECT().LatestBlock().Return(big.NewInt(250), nil)
+	// Chain is scanned from sweep start in ranges
+	gomock.InOrder(
+		s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, q ethereum.FilterQuery) ([]types.Log, error) {
+			s.Equal(big.NewInt(20), q.FromBlock)
+			s.Equal(big.NewInt(119), q.ToBlock)
+			return []types.Log{proposalEventLog(30, 1, 5, ProposalStatusPassed, dataHash)}, nil
+		}),
+		s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, q ethereum.FilterQuery) ([]types.Log, error) {
+			s.Equal(big.NewInt(120), q.FromBlock)
+			s.Equal(big.NewInt(219), q.ToBlock)
+			return []types.Log{}, nil
+		}),
+		s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, q ethereum.FilterQuery) ([]types.Log, error) {
+			s.Equal(big.NewInt(220), q.FromBlock)
+			s.Equal(big.NewInt(250), q.ToBlock)
+			return []types.Log{}, nil
+		}),
+	)
+	s.Nil(w.scanProposalEvents(big.NewInt(250)))
+
+	// Passed proposal and swept block are restored, scan continues from the next block
+	restarted := NewWriter(s.client, w.cfg, make(chan struct{}), make(chan error), nil)
+	s.Nil(restarted.SetProposalStore(store))
+	restarted.SetExecutionSweepStart(big.NewInt(20))
+	s.Equal(big.NewInt(250), restarted.sweptBlock)
+	s.Equal(&passedProposal{dataHash: dataHash, block: big.NewInt(30)}, restarted.passed[proposalKey{source: 1, nonce: 5}])
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, q ethereum.FilterQuery) ([]types.Log, error) {
+		s.Equal(big.NewInt(251), q.FromBlock)
+		return []types.Log{proposalEventLog(255, 1, 5, ProposalStatusTransferred, dataHash)}, nil
+	})
+	s.Nil(restarted.scanProposalEvents(big.NewInt(260)))
+	passed, err := store.Passed()
+	s.Nil(err)
+	s.Len(passed, 0)
+}
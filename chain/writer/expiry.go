@@ -0,0 +1,155 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package writer
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/proposalstore"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+)
+
+// Time between checks of voted proposals for expiry
+var ExpirySweepInterval = time.Minute * 5
+
+type proposalKey struct {
+	source utils.ChainId
+	nonce  utils.Nonce
+}
+
+type activeProposal struct {
+	m        *utils.Message
+	dataHash ethcommon.Hash
+	reported bool // expiry was already reported to an operator
+}
+
+// trackProposal remembers voted proposal so it could be cancelled once expired
+func (w *writer) trackProposal(m *utils.Message, dataHash ethcommon.Hash) {
+	w.activeLock.Lock()
+	defer w.activeLock.Unlock()
+	w.active[proposalKey{source: m.Source, nonce: m.DepositNonce}] = &activeProposal{m: m, dataHash: dataHash}
+	if w.proposals != nil {
+		err := w.proposals.PutVoted(&proposalstore.VotedProposal{Message: m, DataHash: dataHash})
+		if err != nil {
+			log.Error().Err(err).Interface("src", m.Source).Interface("nonce", m.DepositNonce).Msg("Failed to persist voted proposal")
+		}
+	}
+}
+
+func (w *writer) untrackProposal(key proposalKey) {
+	w.activeLock.Lock()
+	defer w.activeLock.Unlock()
+	delete(w.active, key)
+	if w.proposals != nil {
+		err := w.proposals.DeleteVoted(key.source, key.nonce)
+		if err != nil {
+			log.Error().Err(err).Interface("src", key.source).Interface("nonce", key.nonce).Msg("Failed to remove voted proposal")
+		}
+	}
+}
+
+// StartExpirySweeper periodically checks proposals voted by this relayer and cancels expired ones
+func (w *writer) StartExpirySweeper() {
+	go func() {
+		ticker := time.NewTicker(ExpirySweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				w.sweepExpiredProposals()
+			}
+		}
+	}()
+}
+
+// sweepExpiredProposals cancels proposals that stayed active longer than bridge expiry if relayer has admin role,
+// otherwise they are reported for an operator to cancel
+func (w *writer) sweepExpiredProposals() {
+	w.activeLock.Lock()
+	proposals := make(map[proposalKey]*activeProposal, len(w.active))
+	for k, p := range w.active {
+		proposals[k] = p
+	}
+	w.activeLock.Unlock()
+	if len(proposals) == 0 {
+		return
+	}
+
+	expiry, err := w.bridgeContract.Expiry(w.client.CallOpts())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get bridge expiry")
+		return
+	}
+	latestBlock, err := w.client.LatestBlock()
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to fetch latest block")
+		return
+	}
+	var isAdmin *bool
+	for key, p := range proposals {
+		prop, err := w.bridgeContract.GetProposal(w.client.CallOpts(), uint8(key.source), uint64(key.nonce), p.dataHash)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to check proposal existence")
+			continue
+		}
+		if prop.Status == ProposalStatusPassed || prop.Status == ProposalStatusTransferred || prop.Status == ProposalStatusCancelled {
+			w.untrackProposal(key)
+			continue
+		}
+		if prop.Status != ProposalNotPassedStatus || new(big.Int).Sub(latestBlock, prop.ProposedBlock).Cmp(expiry) <= 0 {
+			continue
+		}
+		if isAdmin == nil {
+			admin := w.hasAdminRole()
+			isAdmin = &admin
+		}
+		if *isAdmin {
+			if w.cancelProposal(p.m, p.dataHash) {
+				w.untrackProposal(key)
+			}
+			continue
+		}
+		if !p.reported {
+			log.Warn().Bool("alert", true).Interface("src", key.source).Interface("dst", p.m.Destination).Interface("nonce", key.nonce).Str("dataHash", p.dataHash.Hex()).Str("proposedBlock", prop.ProposedBlock.String()).Msg("Proposal expired, relayer is not admin. Operator should cancel it with `cbcli bridge cancel-proposal`")
+			p.reported = true
+		}
+	}
+}
+
+// hasAdminRole returns true if relayer has default admin role on bridge
+func (w *writer) hasAdminRole() bool {
+	role, err := w.bridgeContract.DEFAULTADMINROLE(w.client.CallOpts())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get admin role")
+		return false
+	}
+	isAdmin, err := w.bridgeContract.HasRole(w.client.CallOpts(), role, w.client.Opts().From)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to check admin role")
+		return false
+	}
+	return isAdmin
+}
+
+// cancelProposal submits cancellation of expired proposal
+func (w *writer) cancelProposal(m *utils.Message, dataHash ethcommon.Hash) bool {
+	err := w.client.LockAndUpdateOpts()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update tx opts")
+		return false
+	}
+	tx, err := w.bridgeContract.CancelProposal(w.client.Opts(), uint8(m.Source), uint64(m.DepositNonce), dataHash)
+	w.client.UnlockOpts()
+	if err != nil {
+		log.Error().Err(err).Interface("src", m.Source).Interface("nonce", m.DepositNonce).Msg("Failed to cancel expired proposal")
+		return false
+	}
+	log.Info().Str("tx", tx.Hash().Hex()).Interface("src", m.Source).Interface("nonce", m.DepositNonce).Msg("Submitted cancellation of expired proposal")
+	return true
+}
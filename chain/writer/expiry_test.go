@@ -0,0 +1,105 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+package writer
+
+import (
+	"math/big"
+	"os"
+
+	"github.com/ChainSafe/chainbridge-celo/bindings/Bridge"
+	"github.com/ChainSafe/chainbridge-celo/chain/config"
+	"github.com/ChainSafe/chainbridge-celo/proposalstore"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/mock/gomock"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+func (s *WriterTestSuite) newExpiryWriter() (*writer, *utils.Message) {
+	cfg := &config.CeloChainConfig{StartBlock: big.NewInt(1), BridgeContract: common.Address{}}
+	w := NewWriter(s.client, cfg, make(chan struct{}), make(chan error), nil)
+	w.SetBridge(s.bridgeMock)
+	m := utils.NewFungibleTransfer(1, 0, utils.Nonce(555), [32]byte{1}, nil, nil, big.NewInt(10), make([]byte, 32))
+	w.trackProposal(m, common.Hash{1})
+	return w, m
+}
+
+func (s *WriterTestSuite) TestSweepCancelsExpiredProposalAsAdmin() {
+	w, m := s.newExpiryWriter()
+	s.client.EXPECT().CallOpts().Return(nil).AnyTimes()
+	s.client.EXPECT().Opts().Return(&bind.TransactOpts{}).AnyTimes()
+	s.client.EXPECT().LatestBlock().Return(big.NewInt(200), nil)
+	s.bridgeMock.EXPECT().Expiry(gomock.Any()).Return(big.NewInt(100), nil)
+	s.bridgeMock.EXPECT().GetProposal(gomock.Any(), uint8(m.Source), uint64(m.DepositNonce), [32]byte(common.Hash{1})).Return(Bridge.BridgeProposal{Status: ProposalNotPassedStatus, ProposedBlock: big.NewInt(50)}, nil)
+	s.bridgeMock.EXPECT().DEFAULTADMINROLE(gomock.Any()).Return([32]byte{}, nil)
+	s.bridgeMock.EXPECT().HasRole(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+	s.client.EXPECT().LockAndUpdateOpts().Return(nil)
+	s.bridgeMock.EXPECT().CancelProposal(gomock.Any(), uint8(m.Source), uint64(m.DepositNonce), [32]byte(common.Hash{1})).Return(types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil, nil, nil, nil), nil)
+	s.client.EXPECT().UnlockOpts()
+
+	w.sweepExpiredProposals()
+	s.Equal(0, len(w.active))
+}
+
+func (s *WriterTestSuite) TestSweepReportsExpiredProposalOnce() {
+	w, m := s.newExpiryWriter()
+	s.client.EXPECT().CallOpts().Return(nil).AnyTimes()
+	s.client.EXPECT().Opts().Return(&bind.TransactOpts{}).AnyTimes()
+	s.client.EXPECT().LatestBlock().Return(big.NewInt(200), nil).Times(2)
+	s.bridgeMock.EXPECT().Expiry(gomock.Any()).Return(big.NewInt(100), nil).Times(2)
+	s.bridgeMock.EXPECT().GetProposal(gomock.Any(), uint8(m.Source), uint64(m.DepositNonce), gomock.Any()).Return(Bridge.BridgeProposal{Status: ProposalNotPassedStatus, ProposedBlock: big.NewInt(50)}, nil).Times(2)
+	s.bridgeMock.EXPECT().DEFAULTADMINROLE(gomock.Any()).Return([32]byte{}, nil).Times(2)
+	s.bridgeMock.EXPECT().HasRole(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).Times(2)
+
+	// CancelProposal should not be called without admin role
+	w.sweepExpiredProposals()
+	w.sweepExpiredProposals()
+	s.Equal(1, len(w.active))
+	s.True(w.active[proposalKey{source: m.Source, nonce: m.DepositNonce}].reported)
+}
+
+func (s *WriterTestSuite) TestSweepSkipsNotExpiredAndUntracksPassed() {
+	w, m := s.newExpiryWriter()
+	s.client.EXPECT().CallOpts().Return(nil).AnyTimes()
+	s.client.EXPECT().LatestBlock().Return(big.NewInt(100), nil).Times(2)
+	s.bridgeMock.EXPECT().Expiry(gomock.Any()).Return(big.NewInt(100), nil).Times(2)
+	s.bridgeMock.EXPECT().GetProposal(gomock.Any(), uint8(m.Source), uint64(m.DepositNonce), gomock.Any()).Return(Bridge.BridgeProposal{Status: ProposalNotPassedStatus, ProposedBlock: big.NewInt(50)}, nil)
+	w.sweepExpiredProposals()
+	s.Equal(1, len(w.active))
+
+	s.bridgeMock.EXPECT().GetProposal(gomock.Any(), uint8(m.Source), uint64(m.DepositNonce), gomock.Any()).Return(Bridge.BridgeProposal{Status: ProposalStatusPassed, ProposedBlock: big.NewInt(50)}, nil)
+	w.sweepExpiredProposals()
+	s.Equal(0, len(w.active))
+}
+
+func (s *WriterTestSuite) TestVotedProposalsSurviveRestart() {
+	db, err := leveldb.OpenFile("./test/db", nil)
+	s.Nil(err)
+	defer os.RemoveAll("./test")
+	defer db.Close()
+	store := proposalstore.NewStore(db, 0)
+
+	w, m := s.newExpiryWriter()
+	s.Nil(w.SetProposalStore(store))
+	// Proposals voted once store is set are persisted
+	w.trackProposal(m, common.Hash{1})
+
+	restarted := NewWriter(s.client, w.cfg, make(chan struct{}), make(chan error), nil)
+	restarted.SetBridge(s.bridgeMock)
+	s.Nil(restarted.SetProposalStore(store))
+	s.Equal(1, len(restarted.active))
+	p := restarted.active[proposalKey{source: m.Source, nonce: m.DepositNonce}]
+	s.Equal(common.Hash{1}, p.dataHash)
+	s.Equal(m.DepositNonce, p.m.DepositNonce)
+
+	s.client.EXPECT().CallOpts().Return(nil).AnyTimes()
+	s.client.EXPECT().LatestBlock().Return(big.NewInt(100), nil)
+	s.bridgeMock.EXPECT().Expiry(gomock.Any()).Return(big.NewInt(100), nil)
+	s.bridgeMock.EXPECT().GetProposal(gomock.Any(), uint8(m.Source), uint64(m.DepositNonce), gomock.Any()).Return(Bridge.BridgeProposal{Status: ProposalStatusCancelled}, nil)
+	restarted.sweepExpiredProposals()
+	voted, err := store.Voted()
+	s.Nil(err)
+	s.Len(voted, 0)
+}
@@ -7,6 +7,7 @@ package mock_writer
 import (
 	context "context"
 	Bridge "github.com/ChainSafe/chainbridge-celo/bindings/Bridge"
+	utils "github.com/ChainSafe/chainbridge-celo/utils"
 	ethereum "github.com/ethereum/go-ethereum"
 	bind "github.com/ethereum/go-ethereum/accounts/abi/bind"
 	common "github.com/ethereum/go-ethereum/common"
@@ -99,6 +100,241 @@ func (mr *MockBridgerMockRecorder) ExecuteProposal(opts, chainID, depositNonce,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteProposal", reflect.TypeOf((*MockBridger)(nil).ExecuteProposal), opts, chainID, depositNonce, data, resourceID, signatureHeader, aggregatePublicKey, hashedMessage, rootHash, key, nodes)
 }
 
+// Expiry mocks base method
+func (m *MockBridger) Expiry(opts *bind.CallOpts) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expiry", opts)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expiry indicates an expected call of Expiry
+func (mr *MockBridgerMockRecorder) Expiry(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expiry", reflect.TypeOf((*MockBridger)(nil).Expiry), opts)
+}
+
+// CancelProposal mocks base method
+func (m *MockBridger) CancelProposal(opts *bind.TransactOpts, chainID uint8, depositNonce uint64, dataHash [32]byte) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelProposal", opts, chainID, depositNonce, dataHash)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelProposal indicates an expected call of CancelProposal
+func (mr *MockBridgerMockRecorder) CancelProposal(opts, chainID, depositNonce, dataHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelProposal", reflect.TypeOf((*MockBridger)(nil).CancelProposal), opts, chainID, depositNonce, dataHash)
+}
+
+// DEFAULTADMINROLE mocks base method
+func (m *MockBridger) DEFAULTADMINROLE(opts *bind.CallOpts) ([32]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DEFAULTADMINROLE", opts)
+	ret0, _ := ret[0].([32]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DEFAULTADMINROLE indicates an expected call of DEFAULTADMINROLE
+func (mr *MockBridgerMockRecorder) DEFAULTADMINROLE(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DEFAULTADMINROLE", reflect.TypeOf((*MockBridger)(nil).DEFAULTADMINROLE), opts)
+}
+
+// HasRole mocks base method
+func (m *MockBridger) HasRole(opts *bind.CallOpts, role [32]byte, account common.Address) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasRole", opts, role, account)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasRole indicates an expected call of HasRole
+func (mr *MockBridgerMockRecorder) HasRole(opts, role, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasRole", reflect.TypeOf((*MockBridger)(nil).HasRole), opts, role, account)
+}
+
+// MockVolumeLimiter is a mock of VolumeLimiter interface
+type MockVolumeLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockVolumeLimiterMockRecorder
+}
+
+// MockVolumeLimiterMockRecorder is the mock recorder for MockVolumeLimiter
+type MockVolumeLimiterMockRecorder struct {
+	mock *MockVolumeLimiter
+}
+
+// NewMockVolumeLimiter creates a new mock instance
+func NewMockVolumeLimiter(ctrl *gomock.Controller) *MockVolumeLimiter {
+	mock := &MockVolumeLimiter{ctrl: ctrl}
+	mock.recorder = &MockVolumeLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockVolumeLimiter) EXPECT() *MockVolumeLimiterMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method
+func (m_2 *MockVolumeLimiter) Check(m *utils.Message) bool {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Check", m)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Check indicates an expected call of Check
+func (mr *MockVolumeLimiterMockRecorder) Check(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockVolumeLimiter)(nil).Check), m)
+}
+
+// Released mocks base method
+func (m *MockVolumeLimiter) Released(rId utils.ResourceId) <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Released", rId)
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// Released indicates an expected call of Released
+func (mr *MockVolumeLimiterMockRecorder) Released(rId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Released", reflect.TypeOf((*MockVolumeLimiter)(nil).Released), rId)
+}
+
+// MockApprovalQueue is a mock of ApprovalQueue interface
+type MockApprovalQueue struct {
+	ctrl     *gomock.Controller
+	recorder *MockApprovalQueueMockRecorder
+}
+
+// MockApprovalQueueMockRecorder is the mock recorder for MockApprovalQueue
+type MockApprovalQueueMockRecorder struct {
+	mock *MockApprovalQueue
+}
+
+// NewMockApprovalQueue creates a new mock instance
+func NewMockApprovalQueue(ctrl *gomock.Controller) *MockApprovalQueue {
+	mock := &MockApprovalQueue{ctrl: ctrl}
+	mock.recorder = &MockApprovalQueueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockApprovalQueue) EXPECT() *MockApprovalQueueMockRecorder {
+	return m.recorder
+}
+
+// RequiresApproval mocks base method
+func (m_2 *MockApprovalQueue) RequiresApproval(m *utils.Message) bool {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RequiresApproval", m)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// RequiresApproval indicates an expected call of RequiresApproval
+func (mr *MockApprovalQueueMockRecorder) RequiresApproval(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequiresApproval", reflect.TypeOf((*MockApprovalQueue)(nil).RequiresApproval), m)
+}
+
+// Park mocks base method
+func (m_2 *MockApprovalQueue) Park(m *utils.Message) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Park", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Park indicates an expected call of Park
+func (mr *MockApprovalQueueMockRecorder) Park(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Park", reflect.TypeOf((*MockApprovalQueue)(nil).Park), m)
+}
+
+// MockProposalObserver is a mock of ProposalObserver interface
+type MockProposalObserver struct {
+	ctrl     *gomock.Controller
+	recorder *MockProposalObserverMockRecorder
+}
+
+// MockProposalObserverMockRecorder is the mock recorder for MockProposalObserver
+type MockProposalObserverMockRecorder struct {
+	mock *MockProposalObserver
+}
+
+// NewMockProposalObserver creates a new mock instance
+func NewMockProposalObserver(ctrl *gomock.Controller) *MockProposalObserver {
+	mock := &MockProposalObserver{ctrl: ctrl}
+	mock.recorder = &MockProposalObserverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockProposalObserver) EXPECT() *MockProposalObserverMockRecorder {
+	return m.recorder
+}
+
+// Expect mocks base method
+func (m_2 *MockProposalObserver) Expect(m *utils.Message, dataHash common.Hash) {
+	m_2.ctrl.T.Helper()
+	m_2.ctrl.Call(m_2, "Expect", m, dataHash)
+}
+
+// Expect indicates an expected call of Expect
+func (mr *MockProposalObserverMockRecorder) Expect(m, dataHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expect", reflect.TypeOf((*MockProposalObserver)(nil).Expect), m, dataHash)
+}
+
+// MockDepositFetcher is a mock of DepositFetcher interface
+type MockDepositFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockDepositFetcherMockRecorder
+}
+
+// MockDepositFetcherMockRecorder is the mock recorder for MockDepositFetcher
+type MockDepositFetcherMockRecorder struct {
+	mock *MockDepositFetcher
+}
+
+// NewMockDepositFetcher creates a new mock instance
+func NewMockDepositFetcher(ctrl *gomock.Controller) *MockDepositFetcher {
+	mock := &MockDepositFetcher{ctrl: ctrl}
+	mock.recorder = &MockDepositFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDepositFetcher) EXPECT() *MockDepositFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchDeposit mocks base method
+func (m *MockDepositFetcher) FetchDeposit(dest utils.ChainId, nonce utils.Nonce) (*utils.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchDeposit", dest, nonce)
+	ret0, _ := ret[0].(*utils.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchDeposit indicates an expected call of FetchDeposit
+func (mr *MockDepositFetcherMockRecorder) FetchDeposit(dest, nonce interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchDeposit", reflect.TypeOf((*MockDepositFetcher)(nil).FetchDeposit), dest, nonce)
+}
+
 // MockContractCaller is a mock of ContractCaller interface
 type MockContractCaller struct {
 	ctrl     *gomock.Controller
@@ -167,6 +403,21 @@ func (mr *MockContractCallerMockRecorder) BlockByNumber(ctx, number interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockByNumber", reflect.TypeOf((*MockContractCaller)(nil).BlockByNumber), ctx, number)
 }
 
+// HeaderByNumber mocks base method
+func (m *MockContractCaller) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeaderByNumber", ctx, number)
+	ret0, _ := ret[0].(*types.Header)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeaderByNumber indicates an expected call of HeaderByNumber
+func (mr *MockContractCallerMockRecorder) HeaderByNumber(ctx, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeaderByNumber", reflect.TypeOf((*MockContractCaller)(nil).HeaderByNumber), ctx, number)
+}
+
 // CallOpts mocks base method
 func (m *MockContractCaller) CallOpts() *bind.CallOpts {
 	m.ctrl.T.Helper()
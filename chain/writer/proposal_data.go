@@ -5,13 +5,26 @@ package writer
 
 import (
 	"bytes"
-	"github.com/ChainSafe/chainbridge-celo/utils"
+	"errors"
+	"fmt"
 	"math/big"
 
+	"github.com/ChainSafe/chainbridge-celo/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+var ErrMalformedProposalData = errors.New("malformed proposal data")
+
+// ProposalData is proposal data decoded into typed fields. Fields not used by transfer type are nil
+type ProposalData struct {
+	Type      utils.TransferType
+	Amount    *big.Int // Amount of fungible transfer
+	TokenID   *big.Int // Token of non-fungible transfer
+	Recipient []byte
+	Metadata  []byte
+}
+
 // constructErc20ProposalData returns the bytes to construct a proposal suitable for Erc20
 func ConstructErc20ProposalData(amount []byte, recipient []byte) []byte {
 	b := bytes.Buffer{}
@@ -59,3 +72,122 @@ func CreateProposalDataHash(data []byte, handler common.Address, mp *utils.Merkl
 	b.Write(sv.Signature)
 	return crypto.Keccak256Hash(b.Bytes())
 }
+
+// ConstructProposalData returns the bytes of proposal relayer votes on for message
+func ConstructProposalData(m *utils.Message) ([]byte, error) {
+	switch m.Type {
+	case utils.FungibleTransfer:
+		return erc20ProposalData(m)
+	case utils.NonFungibleTransfer:
+		return erc721ProposalData(m)
+	case utils.GenericTransfer:
+		return genericProposalData(m)
+	default:
+		return nil, fmt.Errorf("unknown message type received %s", m.Type)
+	}
+}
+
+func erc20ProposalData(m *utils.Message) ([]byte, error) {
+	if len(m.Payload) != 2 {
+		return nil, errors.New("malformed payload. Len  of payload should be 2")
+	}
+	amount, ok := m.Payload[0].([]byte)
+	if !ok {
+		return nil, errors.New("wrong payloads amount format")
+	}
+
+	recipient, ok := m.Payload[1].([]byte)
+	if !ok {
+		return nil, errors.New("wrong payloads recipient format")
+	}
+	return ConstructErc20ProposalData(amount, recipient), nil
+}
+
+func erc721ProposalData(m *utils.Message) ([]byte, error) {
+	if len(m.Payload) != 3 {
+		return nil, errors.New("malformed payload. Len  of payload should be 3")
+	}
+	tokenID, ok := m.Payload[0].([]byte)
+	if !ok {
+		return nil, errors.New("wrong payloads tokenID format")
+	}
+	recipient, ok := m.Payload[1].([]byte)
+	if !ok {
+		return nil, errors.New("wrong payloads recipient format")
+	}
+	metadata, ok := m.Payload[2].([]byte)
+	if !ok {
+		return nil, errors.New("wrong payloads metadata format")
+	}
+	return ConstructErc721ProposalData(tokenID, recipient, metadata), nil
+}
+
+func genericProposalData(m *utils.Message) ([]byte, error) {
+	if len(m.Payload) != 1 {
+		return nil, errors.New("malformed payload. Len  of payload should be 1")
+	}
+	metadata, ok := m.Payload[0].([]byte)
+	if !ok {
+		return nil, errors.New("unable to convert metadata to []byte")
+	}
+	return ConstructGenericProposalData(metadata), nil
+}
+
+// DecodeProposalData parses data produced by Construct*ProposalData of transfer type t
+func DecodeProposalData(t utils.TransferType, data []byte) (*ProposalData, error) {
+	r := &proposalDataReader{data: data}
+	res := &ProposalData{Type: t}
+	switch t {
+	case utils.FungibleTransfer:
+		res.Amount = r.uint256()
+		res.Recipient = r.bytes()
+	case utils.NonFungibleTransfer:
+		res.TokenID = r.uint256()
+		res.Recipient = r.bytes()
+		res.Metadata = r.bytes()
+	case utils.GenericTransfer:
+		res.Metadata = r.bytes()
+	default:
+		return nil, fmt.Errorf("unknown transfer type %s", t)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.data) != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrMalformedProposalData, len(r.data))
+	}
+	return res, nil
+}
+
+// proposalDataReader reads 32 bytes words and length prefixed byte arrays keeping the first error
+type proposalDataReader struct {
+	data []byte
+	err  error
+}
+
+func (r *proposalDataReader) uint256() *big.Int {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < 32 {
+		r.err = fmt.Errorf("%w: expected 32 bytes word, %d bytes left", ErrMalformedProposalData, len(r.data))
+		return nil
+	}
+	v := new(big.Int).SetBytes(r.data[:32])
+	r.data = r.data[32:]
+	return v
+}
+
+func (r *proposalDataReader) bytes() []byte {
+	length := r.uint256()
+	if r.err != nil {
+		return nil
+	}
+	if !length.IsUint64() || length.Uint64() > uint64(len(r.data)) {
+		r.err = fmt.Errorf("%w: length %s exceeds %d bytes left", ErrMalformedProposalData, length, len(r.data))
+		return nil
+	}
+	v := r.data[:length.Uint64()]
+	r.data = r.data[length.Uint64():]
+	return v
+}
@@ -4,6 +4,8 @@ package writer
 
 import (
 	"bytes"
+	"errors"
+	"math/big"
 	"testing"
 
 	"github.com/ChainSafe/chainbridge-celo/utils"
@@ -45,3 +47,65 @@ func TestCreateProposalDataHash(t *testing.T) {
 	}
 
 }
+
+func TestDecodeProposalData(t *testing.T) {
+	erc20 := utils.NewFungibleTransfer(1, 2, 3, utils.ResourceId{1}, nil, nil, big.NewInt(1000), []byte{4, 5})
+	data, err := ConstructProposalData(erc20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeProposalData(utils.FungibleTransfer, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Amount.Cmp(big.NewInt(1000)) != 0 || !bytes.Equal(decoded.Recipient, []byte{4, 5}) {
+		t.Errorf("unexpected decoded erc20 proposal data %+v", decoded)
+	}
+
+	erc721 := utils.NewNonFungibleTransfer(1, 2, 3, utils.ResourceId{1}, nil, nil, big.NewInt(7), []byte{4, 5}, []byte{6})
+	data, err = ConstructProposalData(erc721)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err = DecodeProposalData(utils.NonFungibleTransfer, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.TokenID.Cmp(big.NewInt(7)) != 0 || !bytes.Equal(decoded.Recipient, []byte{4, 5}) || !bytes.Equal(decoded.Metadata, []byte{6}) {
+		t.Errorf("unexpected decoded erc721 proposal data %+v", decoded)
+	}
+
+	generic := utils.NewGenericTransfer(1, 2, 3, utils.ResourceId{1}, nil, nil, []byte{8, 9})
+	data, err = ConstructProposalData(generic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err = DecodeProposalData(utils.GenericTransfer, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.Metadata, []byte{8, 9}) || decoded.Amount != nil {
+		t.Errorf("unexpected decoded generic proposal data %+v", decoded)
+	}
+}
+
+func TestDecodeProposalDataMalformed(t *testing.T) {
+	data := ConstructErc20ProposalData(big.NewInt(1000).Bytes(), []byte{4, 5})
+	_, err := DecodeProposalData(utils.FungibleTransfer, data[:len(data)-1])
+	if !errors.Is(err, ErrMalformedProposalData) {
+		t.Errorf("expected malformed data error for truncated data got %v", err)
+	}
+	_, err = DecodeProposalData(utils.FungibleTransfer, append(data, 0))
+	if !errors.Is(err, ErrMalformedProposalData) {
+		t.Errorf("expected malformed data error for trailing bytes got %v", err)
+	}
+	// Erc20 data is too short to be erc721 data
+	_, err = DecodeProposalData(utils.NonFungibleTransfer, data)
+	if !errors.Is(err, ErrMalformedProposalData) {
+		t.Errorf("expected malformed data error for wrong type got %v", err)
+	}
+	_, err = DecodeProposalData("Unknown", data)
+	if err == nil {
+		t.Error("expected unknown transfer type error got nil")
+	}
+}
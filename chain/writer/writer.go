@@ -4,11 +4,14 @@
 package writer
 
 import (
+	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/ChainSafe/chainbridge-celo/bindings/Bridge"
 	"github.com/ChainSafe/chainbridge-celo/chain/client"
 	"github.com/ChainSafe/chainbridge-celo/chain/config"
+	"github.com/ChainSafe/chainbridge-celo/proposalstore"
 	"github.com/ChainSafe/chainbridge-celo/utils"
 	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -30,6 +33,16 @@ type writer struct {
 	stop           <-chan struct{}
 	sysErr         chan<- error
 	metrics        *metrics.ChainMetrics
+	limiter        VolumeLimiter
+	approvals      ApprovalQueue
+	observer       ProposalObserver
+	active         map[proposalKey]*activeProposal // proposals voted by this relayer that are not passed yet
+	activeLock     sync.Mutex
+	proposals      ProposalStore
+	fetchers       map[utils.ChainId]DepositFetcher
+	passed         map[proposalKey]*passedProposal // passed proposals seen by execution sweeper
+	sweptBlock     *big.Int                        // last block scanned by execution sweeper
+	sweepStart     *big.Int                        // block execution sweeper starts from if chain was never scanned
 }
 
 type Bridger interface {
@@ -37,6 +50,44 @@ type Bridger interface {
 	HasVotedOnProposal(opts *bind.CallOpts, arg0 *big.Int, arg1 [32]byte, arg2 common.Address) (bool, error)
 	VoteProposal(opts *bind.TransactOpts, chainID uint8, depositNonce uint64, resourceID [32]byte, dataHash [32]byte) (*types.Transaction, error)
 	ExecuteProposal(opts *bind.TransactOpts, chainID uint8, depositNonce uint64, data []byte, resourceID [32]byte, signatureHeader []byte, aggregatePublicKey []byte, hashedMessage [32]byte, rootHash [32]byte, key []byte, nodes []byte) (*types.Transaction, error)
+	Expiry(opts *bind.CallOpts) (*big.Int, error)
+	CancelProposal(opts *bind.TransactOpts, chainID uint8, depositNonce uint64, dataHash [32]byte) (*types.Transaction, error)
+	DEFAULTADMINROLE(opts *bind.CallOpts) ([32]byte, error)
+	HasRole(opts *bind.CallOpts, role [32]byte, account common.Address) (bool, error)
+}
+
+// VolumeLimiter enforces per resource volume limits. Released channel is closed once tripped resource is released by an operator
+type VolumeLimiter interface {
+	Check(m *utils.Message) bool
+	Released(rId utils.ResourceId) <-chan struct{}
+}
+
+// ApprovalQueue parks transfers that require manual operator approval before voting
+type ApprovalQueue interface {
+	RequiresApproval(m *utils.Message) bool
+	Park(m *utils.Message) error
+}
+
+// ProposalObserver receives data hashes writer would vote for when running in observer mode
+type ProposalObserver interface {
+	Expect(m *utils.Message, dataHash common.Hash)
+}
+
+// ProposalStore persists proposals tracked by writer so they survive restarts
+type ProposalStore interface {
+	PutVoted(p *proposalstore.VotedProposal) error
+	DeleteVoted(source utils.ChainId, nonce utils.Nonce) error
+	Voted() ([]*proposalstore.VotedProposal, error)
+	PutPassed(p *proposalstore.PassedProposal) error
+	DeletePassed(source utils.ChainId, nonce utils.Nonce) error
+	Passed() ([]*proposalstore.PassedProposal, error)
+	SweptBlock() (*big.Int, error)
+	SetSweptBlock(block *big.Int) error
+}
+
+// DepositFetcher rebuilds message of deposit made on source chain
+type DepositFetcher interface {
+	FetchDeposit(dest utils.ChainId, nonce utils.Nonce) (*utils.Message, error)
 }
 
 type ContractCaller interface {
@@ -51,11 +102,14 @@ type ContractCaller interface {
 // NewWriter creates and returns writer
 func NewWriter(client ContractCaller, cfg *config.CeloChainConfig, stop <-chan struct{}, sysErr chan<- error, m *metrics.ChainMetrics) *writer {
 	return &writer{
-		cfg:     cfg,
-		client:  client,
-		stop:    stop,
-		sysErr:  sysErr,
-		metrics: m,
+		cfg:      cfg,
+		client:   client,
+		stop:     stop,
+		sysErr:   sysErr,
+		metrics:  m,
+		active:   make(map[proposalKey]*activeProposal),
+		fetchers: make(map[utils.ChainId]DepositFetcher),
+		passed:   make(map[proposalKey]*passedProposal),
 	}
 }
 
@@ -64,33 +118,86 @@ func (w *writer) SetBridge(bridge Bridger) {
 	w.bridgeContract = bridge
 }
 
+// SetLimiter enables volume limits enforcement before voting
+func (w *writer) SetLimiter(l VolumeLimiter) {
+	w.limiter = l
+}
+
+// SetApprovalQueue enables parking of transfers that require manual approval
+func (w *writer) SetApprovalQueue(q ApprovalQueue) {
+	w.approvals = q
+}
+
+// SetObserver switches writer to observer mode. Writer never votes or executes proposals and hands computed data hashes to observer instead
+func (w *writer) SetObserver(o ProposalObserver) {
+	w.observer = o
+}
+
+// SetProposalStore persists proposals tracked by writer and loads ones tracked before restart together with the last
+// block scanned by execution sweeper
+func (w *writer) SetProposalStore(s ProposalStore) error {
+	voted, err := s.Voted()
+	if err != nil {
+		return err
+	}
+	passed, err := s.Passed()
+	if err != nil {
+		return err
+	}
+	sweptBlock, err := s.SweptBlock()
+	if err != nil {
+		return err
+	}
+	w.activeLock.Lock()
+	defer w.activeLock.Unlock()
+	for _, p := range voted {
+		w.active[proposalKey{source: p.Message.Source, nonce: p.Message.DepositNonce}] = &activeProposal{m: p.Message, dataHash: p.DataHash}
+	}
+	for _, p := range passed {
+		w.passed[proposalKey{source: p.Source, nonce: p.Nonce}] = &passedProposal{dataHash: p.DataHash, block: new(big.Int).SetUint64(p.Block)}
+	}
+	w.sweptBlock = sweptBlock
+	w.proposals = s
+	if len(voted) > 0 || len(passed) > 0 {
+		log.Info().Interface("chain", w.cfg.ID).Int("voted", len(voted)).Int("passed", len(passed)).Msg("Loaded tracked proposals")
+	}
+	return nil
+}
+
+// SetExecutionSweepStart sets block execution sweeper starts scanning from if chain was never scanned, eg. bridge deployment block
+func (w *writer) SetExecutionSweepStart(block *big.Int) {
+	w.sweepStart = block
+}
+
+// SetDepositFetcher sets fetcher of deposits made on source chain used to recover unexecuted proposals
+func (w *writer) SetDepositFetcher(source utils.ChainId, f DepositFetcher) {
+	w.fetchers[source] = f
+}
+
 // ResolveMessage handles any given message based on type
 // A bool is returned to indicate failure/success
 // this should be ignored except for within tests.
 func (w *writer) ResolveMessage(m *utils.Message) bool {
+	return w.resolveMessage(m, false)
+}
+
+// ResolveApprovedMessage handles message approved by an operator, skipping the approval queue
+func (w *writer) ResolveApprovedMessage(m *utils.Message) bool {
+	return w.resolveMessage(m, true)
+}
+
+func (w *writer) resolveMessage(m *utils.Message, approved bool) bool {
 	log.Info().Str("type", string(m.Type)).Interface("src", m.Source).Interface("dst", m.Destination).Interface("nonce", m.DepositNonce).Str("rId", m.ResourceId.Hex()).Msg("Attempting to resolve message")
-	var data []byte
-	var handlerContract common.Address
-	var err error
-	switch m.Type {
-	case utils.FungibleTransfer:
-		data, err = w.createERC20ProposalData(m)
-		handlerContract = w.cfg.Erc20HandlerContract
-	case utils.NonFungibleTransfer:
-		data, err = w.createErc721ProposalData(m)
-		handlerContract = w.cfg.Erc721HandlerContract
-	case utils.GenericTransfer:
-		data, err = w.createGenericDepositProposalData(m)
-		handlerContract = w.cfg.GenericHandlerContract
-	default:
-		log.Error().Str("type", string(m.Type)).Msg("Unknown message type received")
-		return false
-	}
+	data, handlerContract, err := w.proposalData(m)
 	if err != nil {
-		log.Error().Err(err)
+		log.Error().Err(err).Str("type", string(m.Type)).Msg("Failed to create proposal data")
 		return false
 	}
 	dataHash := CreateProposalDataHash(data, handlerContract, m.MPParams, m.SVParams)
+	if w.observer != nil {
+		w.observer.Expect(m, dataHash)
+		return true
+	}
 
 	if !w.shouldVote(m, dataHash) {
 		if w.proposalIsPassed(m.Source, m.DepositNonce, dataHash) {
@@ -101,6 +208,17 @@ func (w *writer) ResolveMessage(m *utils.Message) bool {
 			return false
 		}
 	}
+	if !approved && w.approvals != nil && w.approvals.RequiresApproval(m) {
+		err := w.approvals.Park(m)
+		if err != nil {
+			log.Error().Err(err).Interface("src", m.Source).Interface("nonce", m.DepositNonce).Msg("Failed to park transfer for approval")
+			return false
+		}
+		return true
+	}
+	if !w.withinLimits(m) {
+		return false
+	}
 	// Capture latest block so when know where to watch from
 	latestBlock, err := w.client.LatestBlock()
 	if err != nil {
@@ -111,7 +229,22 @@ func (w *writer) ResolveMessage(m *utils.Message) bool {
 	// watch for execution event
 	go w.watchThenExecute(m, data, dataHash, latestBlock)
 
-	w.voteProposal(m, dataHash)
+	return w.voteProposal(m, dataHash)
+}
 
-	return true
+// proposalData creates proposal data of message and returns it with address of handler contract
+func (w *writer) proposalData(m *utils.Message) ([]byte, common.Address, error) {
+	switch m.Type {
+	case utils.FungibleTransfer:
+		data, err := w.createERC20ProposalData(m)
+		return data, w.cfg.Erc20HandlerContract, err
+	case utils.NonFungibleTransfer:
+		data, err := w.createErc721ProposalData(m)
+		return data, w.cfg.Erc721HandlerContract, err
+	case utils.GenericTransfer:
+		data, err := w.createGenericDepositProposalData(m)
+		return data, w.cfg.GenericHandlerContract, err
+	default:
+		return nil, common.Address{}, fmt.Errorf("unknown message type received %s", m.Type)
+	}
 }
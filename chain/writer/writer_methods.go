@@ -28,6 +28,7 @@ var ErrNonceTooLow = errors.New("nonce too low")
 var ErrTxUnderpriced = errors.New("replacement transaction underpriced")
 var ErrFatalTx = errors.New("submission of transaction failed")
 var ErrFatalQuery = errors.New("query of chain state failed")
+var errWriterStopped = errors.New("writer stopped")
 
 // proposalIsComplete returns true if the proposal state is either Passed, Transferred or Cancelled
 func (w *writer) proposalIsComplete(srcId utils.ChainId, nonce utils.Nonce, dataHash ethcommon.Hash) bool {
@@ -92,54 +93,34 @@ func (w *writer) shouldVote(m *utils.Message, dataHash ethcommon.Hash) bool {
 	return true
 }
 
-func (w *writer) createERC20ProposalData(m *utils.Message) ([]byte, error) {
-	log.Info().Interface("src", m.Source).Interface("nonce", m.DepositNonce).Msg("Creating erc20 proposal")
-	if len(m.Payload) != 2 {
-		return nil, errors.New("malformed payload. Len  of payload should be 2")
+// withinLimits checks message against volume limits. If the circuit breaker of message resource is tripped
+// it blocks until an operator releases it or the writer is stopped
+func (w *writer) withinLimits(m *utils.Message) bool {
+	if w.limiter == nil || w.limiter.Check(m) {
+		return true
 	}
-	amount, ok := m.Payload[0].([]byte)
-	if !ok {
-		return nil, errors.New("wrong payloads amount format")
+	log.Warn().Interface("src", m.Source).Interface("nonce", m.DepositNonce).Str("rId", m.ResourceId.Hex()).Msg("Resource circuit breaker tripped, waiting for operator release")
+	select {
+	case <-w.limiter.Released(m.ResourceId):
+		return w.withinLimits(m)
+	case <-w.stop:
+		return false
 	}
+}
 
-	recipient, ok := m.Payload[1].([]byte)
-	if !ok {
-		return nil, errors.New("wrong payloads recipient format")
-	}
-	data := ConstructErc20ProposalData(amount, recipient)
-	return data, nil
+func (w *writer) createERC20ProposalData(m *utils.Message) ([]byte, error) {
+	log.Info().Interface("src", m.Source).Interface("nonce", m.DepositNonce).Msg("Creating erc20 proposal")
+	return erc20ProposalData(m)
 }
 
 func (w *writer) createErc721ProposalData(m *utils.Message) ([]byte, error) {
 	log.Info().Interface("src", m.Source).Interface("nonce", m.DepositNonce).Msg("Creating erc721 proposal")
-	if len(m.Payload) != 3 {
-		return nil, errors.New("malformed payload. Len  of payload should be 3")
-	}
-	tokenID, ok := m.Payload[0].([]byte)
-	if !ok {
-		return nil, errors.New("wrong payloads tokenID format")
-	}
-	recipient, ok := m.Payload[1].([]byte)
-	if !ok {
-		return nil, errors.New("wrong payloads recipient format")
-	}
-	metadata, ok := m.Payload[2].([]byte)
-	if !ok {
-		return nil, errors.New("wrong payloads metadata format")
-	}
-	return ConstructErc721ProposalData(tokenID, recipient, metadata), nil
+	return erc721ProposalData(m)
 }
 
 func (w *writer) createGenericDepositProposalData(m *utils.Message) ([]byte, error) {
 	log.Info().Interface("src", m.Source).Interface("nonce", m.DepositNonce).Msg("Creating generic proposal")
-	if len(m.Payload) != 1 {
-		return nil, errors.New("malformed payload. Len  of payload should be 1")
-	}
-	metadata, ok := m.Payload[0].([]byte)
-	if !ok {
-		return nil, errors.New("unable to convert metadata to []byte")
-	}
-	return ConstructGenericProposalData(metadata), nil
+	return genericProposalData(m)
 }
 
 // watchThenExecute watches for the latest block and executes once the matching finalized event is found
@@ -199,17 +180,17 @@ func (w *writer) watchThenExecute(m *utils.Message, data []byte, dataHash ethcom
 }
 
 // voteProposal submits a vote proposal
-// a vote proposal will try to be submitted up to the TxRetryLimit times
-func (w *writer) voteProposal(m *utils.Message, dataHash ethcommon.Hash) {
+// a vote proposal will try to be submitted up to the TxRetryLimit times, false is returned if it was not submitted
+func (w *writer) voteProposal(m *utils.Message, dataHash ethcommon.Hash) bool {
 	for i := 0; i < TxRetryLimit; i++ {
 		select {
 		case <-w.stop:
-			return
+			return false
 		default:
 			// Checking first does proposal complete? If so, we do not need to vote for it
 			if w.proposalIsComplete(m.Source, m.DepositNonce, dataHash) {
 				log.Info().Interface("source", m.Source).Interface("dest", m.Destination).Interface("nonce", m.DepositNonce).Msg("Proposal voting complete on chain")
-				return
+				return true
 			}
 			err := w.client.LockAndUpdateOpts()
 			if err != nil {
@@ -237,24 +218,35 @@ func (w *writer) voteProposal(m *utils.Message, dataHash ethcommon.Hash) {
 				}
 			}
 			log.Info().Str("tx", tx.Hash().Hex()).Interface("src", m.Source).Interface("depositNonce", m.DepositNonce).Msg("Submitted proposal vote")
-			return
+			w.trackProposal(m, dataHash)
+			return true
 		}
 	}
 	log.Error().Interface("source", m.Source).Interface("dest", m.Destination).Interface("nonce", m.DepositNonce).Msg("Submission of Vote transaction failed")
 	w.sysErr <- ErrFatalTx
+	return false
 }
 
 // executeProposal executes the proposal
 func (w *writer) executeProposal(m *utils.Message, data []byte, dataHash ethcommon.Hash) {
+	err := w.submitExecution(m, data, dataHash)
+	if errors.Is(err, ErrFatalTx) {
+		w.sysErr <- ErrFatalTx
+	}
+}
+
+// submitExecution submits proposal execution retrying up to TxRetryLimit times.
+// ErrFatalTx is returned if retries are exceeded
+func (w *writer) submitExecution(m *utils.Message, data []byte, dataHash ethcommon.Hash) error {
 	for i := 0; i < TxRetryLimit; i++ {
 		select {
 		case <-w.stop:
-			return
+			return errWriterStopped
 		default:
 			err := w.client.LockAndUpdateOpts()
 			if err != nil {
 				log.Error().Err(err).Msg("Failed to update nonce")
-				return
+				return err
 			}
 
 			tx, err := w.bridgeContract.ExecuteProposal(
@@ -274,7 +266,7 @@ func (w *writer) executeProposal(m *utils.Message, data []byte, dataHash ethcomm
 
 			if err == nil {
 				log.Info().Interface("source", m.Source).Interface("dest", m.Destination).Interface("nonce", m.DepositNonce).Str("tx", tx.Hash().Hex()).Msg("Submitted proposal execution")
-				return
+				return nil
 			}
 			if err.Error() == ErrNonceTooLow.Error() || err.Error() == ErrTxUnderpriced.Error() {
 				log.Error().Err(err).Msg("Nonce too low, will retry")
@@ -286,12 +278,12 @@ func (w *writer) executeProposal(m *utils.Message, data []byte, dataHash ethcomm
 			// Checking proposal status one more time (Since it could be execute by some other bridge). If it is finalized then we do not need to retry
 			if w.proposalIsFinalized(m.Source, m.DepositNonce, dataHash) {
 				log.Info().Interface("source", m.Source).Interface("dest", m.Destination).Interface("nonce", m.DepositNonce).Msg("Proposal finalized on chain")
-				return
+				return nil
 			}
 		}
 	}
 	log.Error().Interface("source", m.Source).Interface("dest", m.Destination).Interface("nonce", m.DepositNonce).Msg("Submission of Execute transaction failed")
-	w.sysErr <- ErrFatalTx
+	return ErrFatalTx
 }
 
 // buildQuery constructs a query for the bridgeContract by hashing sig to get the event topic
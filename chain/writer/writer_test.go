@@ -166,7 +166,7 @@ func (s *WriterTestSuite) TestVoteProposalAlreadyComplete() {
 		}
 	}()
 
-	w.voteProposal(m, common.Hash{})
+	s.True(w.voteProposal(m, common.Hash{}))
 }
 
 func (s *WriterTestSuite) TestVoteProposalIsNotComplete() {
@@ -199,7 +199,7 @@ func (s *WriterTestSuite) TestVoteProposalIsNotComplete() {
 		}
 	}()
 
-	w.voteProposal(m, common.Hash{})
+	s.True(w.voteProposal(m, common.Hash{}))
 }
 
 func (s *WriterTestSuite) TestVoteProposalUnexpectedErrorOnVote() {
@@ -228,7 +228,7 @@ func (s *WriterTestSuite) TestVoteProposalUnexpectedErrorOnVote() {
 		s.NotNil(err)
 	}()
 
-	w.voteProposal(m, common.Hash{})
+	s.False(w.voteProposal(m, common.Hash{}))
 }
 
 func (s *WriterTestSuite) TestProposalIsNotVotedButExecutedBecauseAlreadyPassed() {
@@ -296,7 +296,7 @@ func (s *WriterTestSuite) TestVoteProposalLockAndUpdateOptsError() {
 		s.NotNil(err)
 	}()
 
-	w.voteProposal(m, common.Hash{})
+	s.False(w.voteProposal(m, common.Hash{}))
 }
 
 func (s *WriterTestSuite) TestExecuteProposalLockAndUpdateOptsError() {
@@ -1143,3 +1143,73 @@ func (s *WriterTestSuite) TestCreateGenericProposalDataComplete() {
 	s.NotNil(result)
 	s.Nil(err)
 }
+
+func (s *WriterTestSuite) TestWithinLimitsWaitsForRelease() {
+	stopChn := make(chan struct{})
+	errChn := make(chan error)
+	m := utils.NewFungibleTransfer(utils.ChainId(1), 0, utils.Nonce(555), [32]byte{1}, nil, nil, big.NewInt(10), make([]byte, 32))
+	cfg := &config.CeloChainConfig{StartBlock: big.NewInt(1), BridgeContract: common.Address{}}
+	w := NewWriter(s.client, cfg, stopChn, errChn, nil)
+	limiter := mock_writer.NewMockVolumeLimiter(s.gomockController)
+	w.SetLimiter(limiter)
+
+	released := make(chan struct{})
+	limiter.EXPECT().Check(m).Return(false)
+	limiter.EXPECT().Released(m.ResourceId).Return(released)
+	limiter.EXPECT().Check(m).Return(true)
+	close(released)
+	s.True(w.withinLimits(m))
+}
+
+func (s *WriterTestSuite) TestWithinLimitsStopped() {
+	stopChn := make(chan struct{})
+	errChn := make(chan error)
+	m := utils.NewFungibleTransfer(utils.ChainId(1), 0, utils.Nonce(555), [32]byte{1}, nil, nil, big.NewInt(10), make([]byte, 32))
+	cfg := &config.CeloChainConfig{StartBlock: big.NewInt(1), BridgeContract: common.Address{}}
+	w := NewWriter(s.client, cfg, stopChn, errChn, nil)
+	limiter := mock_writer.NewMockVolumeLimiter(s.gomockController)
+	w.SetLimiter(limiter)
+
+	limiter.EXPECT().Check(m).Return(false)
+	limiter.EXPECT().Released(m.ResourceId).Return(make(chan struct{}))
+	close(stopChn)
+	s.False(w.withinLimits(m))
+}
+
+func (s *WriterTestSuite) TestResolveMessageParksTransferForApproval() {
+	stopChn := make(chan struct{})
+	errChn := make(chan error)
+	m := utils.NewFungibleTransfer(utils.ChainId(1), 0, utils.Nonce(555), [32]byte{1}, &utils.MerkleProof{}, &utils.SignatureVerification{}, big.NewInt(10), make([]byte, 32))
+	cfg := &config.CeloChainConfig{StartBlock: big.NewInt(1), BridgeContract: common.Address{}}
+	w := NewWriter(s.client, cfg, stopChn, errChn, nil)
+	w.SetBridge(s.bridgeMock)
+	queue := mock_writer.NewMockApprovalQueue(s.gomockController)
+	w.SetApprovalQueue(queue)
+
+	prop := Bridge.BridgeProposal{Status: ProposalNotPassedStatus}
+	s.client.EXPECT().CallOpts().Return(nil)
+	s.bridgeMock.EXPECT().GetProposal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(prop, nil)
+	s.client.EXPECT().CallOpts().Return(nil)
+	s.client.EXPECT().Opts().Return(&bind.TransactOpts{From: common.Address{}})
+	s.bridgeMock.EXPECT().HasVotedOnProposal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+
+	// Parked message should not be voted
+	queue.EXPECT().RequiresApproval(m).Return(true)
+	queue.EXPECT().Park(m).Return(nil)
+	s.True(w.ResolveMessage(m))
+}
+
+func (s *WriterTestSuite) TestResolveMessageObserverDoesNotVote() {
+	stopChn := make(chan struct{})
+	errChn := make(chan error)
+	m := utils.NewFungibleTransfer(utils.ChainId(1), 0, utils.Nonce(555), [32]byte{1}, &utils.MerkleProof{}, &utils.SignatureVerification{}, big.NewInt(10), make([]byte, 32))
+	cfg := &config.CeloChainConfig{StartBlock: big.NewInt(1), BridgeContract: common.Address{}}
+	w := NewWriter(s.client, cfg, stopChn, errChn, nil)
+	w.SetBridge(s.bridgeMock)
+	obs := mock_writer.NewMockProposalObserver(s.gomockController)
+	w.SetObserver(obs)
+
+	// Observer receives data hash and no calls to bridge or client are made
+	obs.EXPECT().Expect(m, gomock.Any())
+	s.True(w.ResolveMessage(m))
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/chainbridge-celo/adminapi"
+	"github.com/ChainSafe/chainbridge-celo/flags"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// newAdminClient creates admin API client of running relayer from flags
+func newAdminClient(ctx *cli.Context) *adminapi.Client {
+	c := adminapi.NewClient(ctx.String(flags.AdminURLFlag.Name))
+	c.SetToken(ctx.String(flags.AdminTokenFlag.Name))
+	return c
+}
+
+// ListApprovals prints transfers parked in approval queues of running relayer
+func ListApprovals(ctx *cli.Context) error {
+	c := newAdminClient(ctx)
+	transfers, err := c.Transfers()
+	if err != nil {
+		return err
+	}
+	if len(transfers) == 0 {
+		log.Info().Msg("Approval queue is empty")
+		return nil
+	}
+	for _, t := range transfers {
+		m := t.Message
+		fmt.Printf("chain: %d source: %d nonce: %d resource: %s amount: %s status: %s parked at: %s", m.Destination, m.Source, m.DepositNonce, m.ResourceId.Hex(), t.Amount, t.Status, t.ParkedAt)
+		if t.Reason != "" {
+			fmt.Printf(" reason: %s", t.Reason)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// ApproveTransfer approves parked transfer so running relayer votes on it
+func ApproveTransfer(ctx *cli.Context) error {
+	chainID := utils.ChainId(ctx.Uint(flags.ChainIDFlag.Name))
+	source := utils.ChainId(ctx.Uint(flags.SourceIDFlag.Name))
+	nonce := utils.Nonce(ctx.Uint64(flags.DepositNonceFlag.Name))
+	c := newAdminClient(ctx)
+	err := c.Approve(chainID, source, nonce)
+	if err != nil {
+		return err
+	}
+	log.Info().Interface("chain", chainID).Interface("source", source).Interface("nonce", nonce).Msg("Transfer approved")
+	return nil
+}
+
+// RejectTransfer rejects parked transfer with provided reason
+func RejectTransfer(ctx *cli.Context) error {
+	reason := ctx.String(flags.ReasonFlag.Name)
+	if reason == "" {
+		return errors.New("--reason is required")
+	}
+	chainID := utils.ChainId(ctx.Uint(flags.ChainIDFlag.Name))
+	source := utils.ChainId(ctx.Uint(flags.SourceIDFlag.Name))
+	nonce := utils.Nonce(ctx.Uint64(flags.DepositNonceFlag.Name))
+	c := newAdminClient(ctx)
+	err := c.Reject(chainID, source, nonce, reason)
+	if err != nil {
+		return err
+	}
+	log.Info().Interface("chain", chainID).Interface("source", source).Interface("nonce", nonce).Msg("Transfer rejected")
+	return nil
+}
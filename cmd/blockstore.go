@@ -0,0 +1,198 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ChainSafe/chainbridge-celo/blockdb"
+	"github.com/ChainSafe/chainbridge-celo/chain/client"
+	"github.com/ChainSafe/chainbridge-celo/chain/config"
+	"github.com/ChainSafe/chainbridge-celo/cmd/cfg"
+	"github.com/ChainSafe/chainbridge-celo/flags"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/urfave/cli/v2"
+)
+
+// openLevelDB opens relayer LevelDB. It fails while running relayer holds the database lock
+func openLevelDB(ctx *cli.Context) (*leveldb.DB, error) {
+	ldb, err := leveldb.OpenFile(ctx.String(flags.LevelDBPath.Name), nil)
+	if err != nil {
+		return nil, fmt.Errorf("levelDB.OpenFile fail, relayer using it should be stopped: %w", err)
+	}
+	return ldb, nil
+}
+
+// chainConfigs returns configured chains or chain provided with flag
+func chainConfigs(ctx *cli.Context) ([]*config.CeloChainConfig, error) {
+	startConfig, err := cfg.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	configs := make([]*config.CeloChainConfig, 0)
+	for _, c := range startConfig.Chains {
+		celoChainConfig, err := config.ParseChainConfig(&c, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ctx.IsSet(flags.ChainIDFlag.Name) && celoChainConfig.ID != utils.ChainId(ctx.Uint(flags.ChainIDFlag.Name)) {
+			continue
+		}
+		configs = append(configs, celoChainConfig)
+	}
+	if len(configs) == 0 {
+		return nil, errors.New("no configured chain matches")
+	}
+	return configs, nil
+}
+
+// chainHead returns latest block of chain using read-only client
+func chainHead(celoChainConfig *config.CeloChainConfig) (*big.Int, error) {
+	chainClient, err := client.NewClient(celoChainConfig.Endpoint, celoChainConfig.Http, nil, celoChainConfig.GasLimit, celoChainConfig.MaxGasPrice, celoChainConfig.GasMultiplier)
+	if err != nil {
+		return nil, err
+	}
+	defer chainClient.Close()
+	return chainClient.LatestBlock()
+}
+
+// checkpointRelayer returns relayer of checkpoint set or rewound by command
+func checkpointRelayer(ctx *cli.Context, celoChainConfig *config.CeloChainConfig) string {
+	if relayer := ctx.String(flags.RelayerFlag.Name); relayer != "" {
+		return relayer
+	}
+	if ctx.Bool(flags.ObserverFlag.Name) {
+		return observerBlockstoreName
+	}
+	return common.HexToAddress(celoChainConfig.From).Hex()
+}
+
+// confirm asks operator to confirm action unless it is confirmed with flag
+func confirm(ctx *cli.Context, in io.Reader, question string) bool {
+	if ctx.Bool(flags.YesFlag.Name) {
+		return true
+	}
+	fmt.Printf("%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// ShowBlockstore prints checkpoints of every relayer of configured chains against chain head
+func ShowBlockstore(ctx *cli.Context) error {
+	configs, err := chainConfigs(ctx)
+	if err != nil {
+		return err
+	}
+	ldb, err := openLevelDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer ldb.Close()
+	for _, celoChainConfig := range configs {
+		checkpoints, err := blockdb.Checkpoints(ldb, celoChainConfig.ID)
+		if err != nil {
+			return err
+		}
+		head, err := chainHead(celoChainConfig)
+		if err != nil {
+			log.Warn().Err(err).Interface("chain", celoChainConfig.ID).Msg("Unable to get chain head")
+		}
+		if head != nil {
+			fmt.Printf("chain: %d (%s) head: %s\n", celoChainConfig.ID, celoChainConfig.Name, head)
+		} else {
+			fmt.Printf("chain: %d (%s) head: unavailable\n", celoChainConfig.ID, celoChainConfig.Name)
+		}
+		if len(checkpoints) == 0 {
+			fmt.Println("  no checkpoints stored")
+		}
+		for _, cp := range checkpoints {
+			if head != nil {
+				fmt.Printf("  relayer: %s checkpoint: %s behind: %s\n", cp.Relayer, cp.Block, new(big.Int).Sub(head, cp.Block))
+			} else {
+				fmt.Printf("  relayer: %s checkpoint: %s\n", cp.Relayer, cp.Block)
+			}
+		}
+	}
+	return nil
+}
+
+// SetBlockstore sets checkpoint of relayer to block which is not after chain head
+func SetBlockstore(ctx *cli.Context) error {
+	if !ctx.IsSet(flags.ChainIDFlag.Name) || !ctx.IsSet(flags.BlockFlag.Name) {
+		return errors.New("--chain and --block should be provided")
+	}
+	configs, err := chainConfigs(ctx)
+	if err != nil {
+		return err
+	}
+	block := new(big.Int).SetUint64(ctx.Uint64(flags.BlockFlag.Name))
+	head, err := chainHead(configs[0])
+	if err != nil {
+		return fmt.Errorf("unable to get chain head: %w", err)
+	}
+	if block.Cmp(head) > 0 {
+		return fmt.Errorf("block %s is after chain head %s", block, head)
+	}
+	return updateCheckpoint(ctx, configs[0], func(*big.Int) (*big.Int, error) { return block, nil })
+}
+
+// RewindBlockstore moves checkpoint of relayer back by number of blocks
+func RewindBlockstore(ctx *cli.Context) error {
+	blocks := ctx.Uint64(flags.BlocksFlag.Name)
+	if !ctx.IsSet(flags.ChainIDFlag.Name) || blocks == 0 {
+		return errors.New("--chain and positive --blocks should be provided")
+	}
+	configs, err := chainConfigs(ctx)
+	if err != nil {
+		return err
+	}
+	return updateCheckpoint(ctx, configs[0], func(current *big.Int) (*big.Int, error) {
+		if current.Sign() == 0 {
+			return nil, errors.New("no checkpoint stored to rewind")
+		}
+		block := new(big.Int).Sub(current, new(big.Int).SetUint64(blocks))
+		if block.Sign() < 0 {
+			block.SetUint64(0)
+		}
+		return block, nil
+	})
+}
+
+// updateCheckpoint sets checkpoint of chain relayer to block computed from current checkpoint after confirmation
+func updateCheckpoint(ctx *cli.Context, celoChainConfig *config.CeloChainConfig, next func(current *big.Int) (*big.Int, error)) error {
+	ldb, err := openLevelDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer ldb.Close()
+	relayer := checkpointRelayer(ctx, celoChainConfig)
+	bs := blockdb.NewLevelDBBlockstore(ldb, celoChainConfig.ID, relayer)
+	current, err := bs.TryLoadLatestBlock()
+	if err != nil {
+		return err
+	}
+	block, err := next(current)
+	if err != nil {
+		return err
+	}
+	if !confirm(ctx, os.Stdin, fmt.Sprintf("Set checkpoint of chain %d relayer %s from %s to %s?", celoChainConfig.ID, relayer, current, block)) {
+		return errors.New("aborted")
+	}
+	err = bs.SetCheckpoint(block)
+	if err != nil {
+		return err
+	}
+	log.Info().Interface("chain", celoChainConfig.ID).Str("relayer", relayer).Str("from", current.String()).Str("to", block.String()).Msg("Blockstore checkpoint updated")
+	return nil
+}
@@ -3,10 +3,14 @@
 package cmd
 
 import (
+	"context"
+	"math/big"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/ChainSafe/chainbridge-celo/adminapi"
+	"github.com/ChainSafe/chainbridge-celo/approval"
 	"github.com/ChainSafe/chainbridge-celo/blockdb"
 	"github.com/ChainSafe/chainbridge-celo/chain"
 	"github.com/ChainSafe/chainbridge-celo/chain/client"
@@ -14,8 +18,14 @@ import (
 	"github.com/ChainSafe/chainbridge-celo/chain/listener"
 	"github.com/ChainSafe/chainbridge-celo/chain/writer"
 	"github.com/ChainSafe/chainbridge-celo/cmd/cfg"
+	"github.com/ChainSafe/chainbridge-celo/deadletter"
 	"github.com/ChainSafe/chainbridge-celo/flags"
+	"github.com/ChainSafe/chainbridge-celo/limiter"
+	"github.com/ChainSafe/chainbridge-celo/observer"
+	"github.com/ChainSafe/chainbridge-celo/proposalstore"
+	"github.com/ChainSafe/chainbridge-celo/reconciler"
 	"github.com/ChainSafe/chainbridge-celo/router"
+	"github.com/ChainSafe/chainbridge-celo/utils"
 	"github.com/ChainSafe/chainbridge-celo/validatorsync"
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -26,6 +36,16 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
+// Name of blockstore file used by relayer running in observer mode
+const observerBlockstoreName = "observer"
+
+// executionSweeper recovers execution of passed proposals using deposits fetched from source chains
+type executionSweeper interface {
+	SetDepositFetcher(source utils.ChainId, f writer.DepositFetcher)
+	SetExecutionSweepStart(block *big.Int)
+	StartExecutionSweeper()
+}
+
 func Run(ctx *cli.Context) error {
 	startConfig, err := cfg.GetConfig(ctx)
 	if err != nil {
@@ -41,42 +61,189 @@ func Run(ctx *cli.Context) error {
 	}
 	validatorsStore := validatorsync.NewValidatorsStore(ldb)
 	defer validatorsStore.Close()
+	err = validatorsStore.Migrate()
+	if err != nil {
+		return err
+	}
+	adminAddr := ctx.String(flags.AdminAddrFlag.Name)
+	adminServer := adminapi.NewServer(adminAddr)
+	adminServer.SetToken(ctx.String(flags.AdminTokenFlag.Name))
+	if adminAddr != "" {
+		err = adminServer.CheckAddr()
+		if err != nil {
+			return err
+		}
+	}
+	observerMode := ctx.Bool(flags.ObserverFlag.Name)
+	if observerMode {
+		log.Info().Msg("Running in observer mode, proposals will not be voted or executed")
+	}
+	fetchers := make(map[utils.ChainId]writer.DepositFetcher)
+	sweepers := make([]executionSweeper, 0)
+	reconcileInterval := ctx.Duration(flags.ReconcileIntervalFlag.Name)
+	reconcilerChains := make([]*reconciler.Chain, 0)
 
 	for _, c := range startConfig.Chains {
 		celoChainConfig, err := config.ParseChainConfig(&c, ctx)
 		if err != nil {
 			return err
 		}
-		kpI, err := keystore.KeypairFromAddress(celoChainConfig.From, keystore.EthChain, celoChainConfig.KeystorePath, celoChainConfig.Insecure)
-		if err != nil {
-			return err
+		// Tripped circuit breakers can only be released through admin API
+		if len(celoChainConfig.VolumeLimits) > 0 && !observerMode && adminAddr == "" {
+			return errors.Errorf("chain %d has volumeLimits, --adminAddr should be set to release tripped circuit breakers", celoChainConfig.ID)
+		}
+		// Parked transfers can only be approved or rejected through admin API
+		if len(celoChainConfig.ApprovalThresholds) > 0 && !observerMode && adminAddr == "" {
+			return errors.Errorf("chain %d has approvalThresholds, --adminAddr should be set to approve parked transfers", celoChainConfig.ID)
+		}
+		// Observer does not sign anything and keeps its own blockstore
+		var kp *secp256k1.Keypair
+		relayerAddress := observerBlockstoreName
+		if !observerMode {
+			kpI, err := keystore.KeypairFromAddress(celoChainConfig.From, keystore.EthChain, celoChainConfig.KeystorePath, celoChainConfig.Insecure)
+			if err != nil {
+				return err
+			}
+			kp, _ = kpI.(*secp256k1.Keypair)
+			relayerAddress = kp.Address()
 		}
-		kp, _ := kpI.(*secp256k1.Keypair)
 
 		chainClient, err := client.NewClient(celoChainConfig.Endpoint, celoChainConfig.Http, kp, celoChainConfig.GasLimit, celoChainConfig.MaxGasPrice, celoChainConfig.GasMultiplier)
 		if err != nil {
 			return err
 		}
+		err = celoChainConfig.ResolveEpochSize(chainClient)
+		if err != nil {
+			return err
+		}
+		log.Info().Interface("chain", celoChainConfig.ID).Uint64("epochSize", celoChainConfig.EpochSize).Msg("Detected epoch size")
+		// Validators known from checkpoint should be stored before listener starts
+		if celoChainConfig.ValidatorsCheckpoint != nil {
+			err = validatorsync.SeedCheckpoint(chainClient, validatorsStore, uint8(celoChainConfig.ID), celoChainConfig.EpochSize, celoChainConfig.ValidatorsCheckpoint)
+			if err != nil {
+				return err
+			}
+		}
+		repaired, err := validatorsStore.RepairAPKs(uint8(celoChainConfig.ID), celoChainConfig.EpochSize)
+		if err != nil {
+			return err
+		}
+		if repaired > 0 {
+			log.Info().Interface("chain", celoChainConfig.ID).Int("epochs", repaired).Msg("Repaired missing aggregated public keys")
+		}
 		// TODO not to abstract should be moved inside chain initialization
-		bdb, err := blockdb.NewBlockStoreDB(kp.Address(), celoChainConfig.BlockstorePath, celoChainConfig.ID, celoChainConfig.FreshStart, celoChainConfig.StartBlock)
+		bdb, err := blockdb.NewBlockStoreDB(ldb, relayerAddress, celoChainConfig.BlockstorePath, celoChainConfig.ID, celoChainConfig.FreshStart, celoChainConfig.StartBlock)
 		if err != nil {
 			return err
 		}
+		deployment := newDeploymentBlock(chainClient, celoChainConfig)
+		// Without checkpoint and startBlock opt listener starts from bridge deployment instead of genesis
+		if celoChainConfig.StartBlock.Sign() == 0 && !celoChainConfig.LatestBlock {
+			err = discoverStartBlock(deployment, celoChainConfig, bdb)
+			if err != nil {
+				return err
+			}
+		}
+		// Blocks before checkpoint can not be verified if their validators were never synced
+		if celoChainConfig.ValidatorsCheckpoint != nil && !celoChainConfig.LatestBlock {
+			err = validatorsync.CheckStartBlock(validatorsStore, uint8(celoChainConfig.ID), celoChainConfig.ValidatorsCheckpoint, celoChainConfig.StartBlock)
+			if err != nil {
+				return err
+			}
+		}
 		// TODO ChainMetrics
 		w := writer.NewWriter(chainClient, celoChainConfig, stopChn, errChn, nil)
+		var obs *observer.Observer
+		if observerMode {
+			obs, err = observer.NewObserver(celoChainConfig.ID, celoChainConfig.BridgeContract, chainClient, stopChn, errChn)
+			if err != nil {
+				return err
+			}
+			w.SetObserver(obs)
+		}
+		if len(celoChainConfig.VolumeLimits) > 0 && !observerMode {
+			var pause func() error
+			if celoChainConfig.VolumeLimitPause {
+				bridgeAddress := celoChainConfig.BridgeContract
+				pause = func() error { return utils.AdminPause(chainClient, bridgeAddress) }
+			}
+			l, err := limiter.NewLimiter(ldb, celoChainConfig.ID, celoChainConfig.VolumeLimits, pause)
+			if err != nil {
+				return err
+			}
+			w.SetLimiter(l)
+			adminServer.RegisterBreaker(l)
+		}
+		if len(celoChainConfig.ApprovalThresholds) > 0 && !observerMode {
+			q := approval.NewQueue(ldb, celoChainConfig.ID, celoChainConfig.ApprovalThresholds)
+			q.SetResolver(w.ResolveApprovedMessage)
+			w.SetApprovalQueue(q)
+			adminServer.RegisterApprovals(q)
+		}
+		if !observerMode {
+			ps := proposalstore.NewStore(ldb, celoChainConfig.ID)
+			err = w.SetProposalStore(ps)
+			if err != nil {
+				return err
+			}
+			err = setExecutionSweepStart(deployment, celoChainConfig, ps, w)
+			if err != nil {
+				return err
+			}
+		}
 		r.Register(celoChainConfig.ID, w)
 
 		l := listener.NewListener(celoChainConfig, chainClient, bdb, stopChn, errChn, r, validatorsStore)
+		if !observerMode {
+			dl := deadletter.NewStore(ldb, celoChainConfig.ID)
+			dl.SetRetrier(l.RetryDeadLetter)
+			l.SetDeadLetters(dl)
+			adminServer.RegisterDeadLetters(dl)
+		}
 		newChain, err := chain.InitializeChain(celoChainConfig, chainClient, l, w, stopChn)
 		if err != nil {
 			return err
 		}
+		// Observer watches proposals on this chain starting from the same block listener does
+		if obs != nil {
+			obs.Start(celoChainConfig.StartBlock)
+		}
 		err = newChain.Start()
 		if err != nil {
 			log.Error().Interface("chain", newChain.ID()).Err(err).Msg("failed to start chain")
 			return err
 		}
-		go validatorsync.SyncBlockValidators(stopChn, errChn, chainClient, validatorsStore, uint8(celoChainConfig.ID), celoChainConfig.EpochSize)
+		if !observerMode {
+			w.StartExpirySweeper()
+			sweepers = append(sweepers, w)
+		}
+		fetchers[celoChainConfig.ID] = l
+		if reconcileInterval > 0 {
+			rc, err := reconcilerChain(celoChainConfig, chainClient, deployment)
+			if err != nil {
+				return err
+			}
+			reconcilerChains = append(reconcilerChains, rc)
+		}
+		go validatorsync.SyncBlockValidators(stopChn, errChn, chainClient, validatorsStore, uint8(celoChainConfig.ID), celoChainConfig.EpochSize, celoChainConfig.ValidatorsSyncWorkers)
+		if celoChainConfig.ValidatorsRetention != nil {
+			go validatorsync.PruneValidators(stopChn, validatorsStore, bdb, uint8(celoChainConfig.ID), celoChainConfig.EpochSize, celoChainConfig.ValidatorsRetention)
+		}
+	}
+
+	for _, s := range sweepers {
+		for id, f := range fetchers {
+			s.SetDepositFetcher(id, f)
+		}
+		s.StartExecutionSweeper()
+	}
+
+	if reconcileInterval > 0 {
+		reconciler.NewReconciler(reconcilerChains).Start(stopChn, reconcileInterval)
+	}
+
+	if adminAddr != "" {
+		adminServer.Start(stopChn, errChn)
 	}
 
 	sysErr := make(chan os.Signal, 1)
@@ -96,3 +263,71 @@ func Run(ctx *cli.Context) error {
 		return nil
 	}
 }
+
+// deploymentBlock looks up bridge deployment block of chain on first use and returns the same block to later callers,
+// so the binary search over contract code runs once per chain
+type deploymentBlock struct {
+	client   *client.Client
+	cfg      *config.CeloChainConfig
+	block    *big.Int
+	resolved bool
+}
+
+func newDeploymentBlock(c *client.Client, cfg *config.CeloChainConfig) *deploymentBlock {
+	return &deploymentBlock{client: c, cfg: cfg}
+}
+
+// Get returns copy of bridge deployment block, nil if node is unable to serve historical state
+func (d *deploymentBlock) Get() (*big.Int, error) {
+	if !d.resolved {
+		latest, err := d.client.LatestBlock()
+		if err != nil {
+			return nil, err
+		}
+		d.block, err = client.FindDeploymentBlock(context.Background(), d.client, d.cfg.BridgeContract, latest)
+		if err != nil {
+			log.Warn().Interface("chain", d.cfg.ID).Err(err).Msg("Unable to find bridge deployment block, node should serve historical state")
+		}
+		d.resolved = true
+	}
+	if d.block == nil {
+		return nil, nil
+	}
+	return new(big.Int).Set(d.block), nil
+}
+
+// discoverStartBlock sets start block to bridge deployment block and persists it as blockstore checkpoint.
+// Start block stays 0 if node is unable to serve historical state
+func discoverStartBlock(deployment *deploymentBlock, celoChainConfig *config.CeloChainConfig, bdb *blockdb.LevelDBBlockstore) error {
+	block, err := deployment.Get()
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		log.Warn().Interface("chain", celoChainConfig.ID).Msg("Starting from block 0")
+		return nil
+	}
+	celoChainConfig.StartBlock.Set(block)
+	log.Info().Interface("chain", celoChainConfig.ID).Str("bridge", celoChainConfig.BridgeContract.Hex()).Str("block", block.String()).Msg("Starting from bridge deployment block")
+	return bdb.SetCheckpoint(block)
+}
+
+// setExecutionSweepStart makes execution sweeper scan chain that was never scanned from bridge deployment block, so
+// proposals that passed long before relayer started are found. Start block of the chain is used if node is unable to
+// serve historical state
+func setExecutionSweepStart(deployment *deploymentBlock, celoChainConfig *config.CeloChainConfig, ps *proposalstore.Store, w executionSweeper) error {
+	swept, err := ps.SweptBlock()
+	if err != nil || swept != nil {
+		return err
+	}
+	block, err := deployment.Get()
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		log.Warn().Interface("chain", celoChainConfig.ID).Str("block", celoChainConfig.StartBlock.String()).Msg("Execution sweeper starts from start block")
+		return nil
+	}
+	w.SetExecutionSweepStart(block)
+	return nil
+}
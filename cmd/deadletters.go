@@ -0,0 +1,44 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/chainbridge-celo/flags"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// ListDeadLetters prints deposits running relayer was unable to route
+func ListDeadLetters(ctx *cli.Context) error {
+	c := newAdminClient(ctx)
+	dls, err := c.DeadLetters()
+	if err != nil {
+		return err
+	}
+	if len(dls) == 0 {
+		log.Info().Msg("There are no dead letters")
+		return nil
+	}
+	for _, dl := range dls {
+		fmt.Printf("source: %d dest: %d nonce: %d resource: %s block: %d tx: %s retries: %d recorded at: %s reason: %s\n", dl.Source, dl.Destination, dl.Nonce, dl.ResourceID.Hex(), dl.Block, dl.TxHash.Hex(), dl.Retries, dl.RecordedAt, dl.Reason)
+	}
+	return nil
+}
+
+// RetryDeadLetter makes running relayer rebuild dead letter deposit and route it again
+func RetryDeadLetter(ctx *cli.Context) error {
+	chainID := utils.ChainId(ctx.Uint(flags.ChainIDFlag.Name))
+	dest := utils.ChainId(ctx.Uint(flags.DestIDFlag.Name))
+	nonce := utils.Nonce(ctx.Uint64(flags.DepositNonceFlag.Name))
+	c := newAdminClient(ctx)
+	err := c.Retry(chainID, dest, nonce)
+	if err != nil {
+		return err
+	}
+	log.Info().Interface("chain", chainID).Interface("dest", dest).Interface("nonce", nonce).Msg("Dead letter retried")
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/chainbridge-celo/flags"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// ListTrippedLimits prints resources which circuit breakers are tripped in running relayer
+func ListTrippedLimits(ctx *cli.Context) error {
+	c := newAdminClient(ctx)
+	tripped, err := c.TrippedResources()
+	if err != nil {
+		return err
+	}
+	if len(tripped) == 0 {
+		log.Info().Msg("No tripped circuit breakers")
+		return nil
+	}
+	for _, t := range tripped {
+		fmt.Printf("chain: %d resource: %s tripped at: %s reason: %s\n", t.ChainID, t.ResourceID.Hex(), t.TrippedAt, t.Reason)
+	}
+	return nil
+}
+
+// ReleaseLimit releases tripped circuit breaker of provided resource in running relayer
+func ReleaseLimit(ctx *cli.Context) error {
+	rIdBytes, err := hexutil.Decode(ctx.String(flags.ResourceIDFlag.Name))
+	if err != nil || len(rIdBytes) != 32 {
+		return fmt.Errorf("invalid resource id %s", ctx.String(flags.ResourceIDFlag.Name))
+	}
+	var rId utils.ResourceId
+	copy(rId[:], rIdBytes)
+	chainID := utils.ChainId(ctx.Uint(flags.ChainIDFlag.Name))
+	c := newAdminClient(ctx)
+	err = c.Release(chainID, rId)
+	if err != nil {
+		return err
+	}
+	log.Info().Interface("chain", chainID).Str("resource", rId.Hex()).Msg("Circuit breaker released")
+	return nil
+}
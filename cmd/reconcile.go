@@ -0,0 +1,86 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	bridgeHandler "github.com/ChainSafe/chainbridge-celo/bindings/Bridge"
+	"github.com/ChainSafe/chainbridge-celo/chain/client"
+	"github.com/ChainSafe/chainbridge-celo/chain/config"
+	"github.com/ChainSafe/chainbridge-celo/flags"
+	"github.com/ChainSafe/chainbridge-celo/reconciler"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// reconcilerChain binds bridge of chain for reconciler. Proposal events are scanned from bridge deployment block,
+// or from block 0 if node is unable to serve historical state
+func reconcilerChain(celoChainConfig *config.CeloChainConfig, c *client.Client, deployment *deploymentBlock) (*reconciler.Chain, error) {
+	bridgeContract, err := bridgeHandler.NewBridge(celoChainConfig.BridgeContract, c)
+	if err != nil {
+		return nil, err
+	}
+	startBlock, err := deployment.Get()
+	if err != nil {
+		return nil, err
+	}
+	if startBlock == nil {
+		log.Warn().Interface("chain", celoChainConfig.ID).Msg("Scanning proposals from block 0")
+		startBlock = big.NewInt(0)
+	}
+	return &reconciler.Chain{
+		ID:         celoChainConfig.ID,
+		Bridge:     celoChainConfig.BridgeContract,
+		Client:     c,
+		Contract:   bridgeContract,
+		StartBlock: startBlock,
+	}, nil
+}
+
+// Reconcile prints report of deposits of every configured chain pair that were never proposed,
+// proposals stuck in Active or Passed status and cancelled proposals
+func Reconcile(ctx *cli.Context) error {
+	format := ctx.String(flags.FormatFlag.Name)
+	if format != "json" && format != "csv" {
+		return fmt.Errorf("unsupported report format %s", format)
+	}
+	configs, err := chainConfigs(ctx)
+	if err != nil {
+		return err
+	}
+	chains := make([]*reconciler.Chain, 0, len(configs))
+	for _, celoChainConfig := range configs {
+		c, err := client.NewClient(celoChainConfig.Endpoint, celoChainConfig.Http, nil, celoChainConfig.GasLimit, celoChainConfig.MaxGasPrice, celoChainConfig.GasMultiplier)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		chain, err := reconcilerChain(celoChainConfig, c, newDeploymentBlock(c, celoChainConfig))
+		if err != nil {
+			return err
+		}
+		chains = append(chains, chain)
+	}
+	report, err := reconciler.NewReconciler(chains).Reconcile()
+	if err != nil {
+		return err
+	}
+	var out io.Writer = os.Stdout
+	if path := ctx.String(flags.OutputFlag.Name); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	if format == "csv" {
+		return report.WriteCSV(out)
+	}
+	return report.WriteJSON(out)
+}
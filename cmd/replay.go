@@ -0,0 +1,227 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/ChainSafe/chainbridge-celo/approval"
+	bridgeHandler "github.com/ChainSafe/chainbridge-celo/bindings/Bridge"
+	erc20Handler "github.com/ChainSafe/chainbridge-celo/bindings/ERC20Handler"
+	erc721Handler "github.com/ChainSafe/chainbridge-celo/bindings/ERC721Handler"
+	"github.com/ChainSafe/chainbridge-celo/bindings/GenericHandler"
+	"github.com/ChainSafe/chainbridge-celo/chain/client"
+	"github.com/ChainSafe/chainbridge-celo/chain/config"
+	"github.com/ChainSafe/chainbridge-celo/chain/listener"
+	"github.com/ChainSafe/chainbridge-celo/chain/writer"
+	"github.com/ChainSafe/chainbridge-celo/cmd/cfg"
+	"github.com/ChainSafe/chainbridge-celo/flags"
+	"github.com/ChainSafe/chainbridge-celo/limiter"
+	"github.com/ChainSafe/chainbridge-celo/proposalstore"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ChainSafe/chainbridge-celo/validatorsync"
+	"github.com/ChainSafe/chainbridge-utils/crypto/secp256k1"
+	"github.com/ChainSafe/chainbridge-utils/keystore"
+	"github.com/rs/zerolog/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/urfave/cli/v2"
+)
+
+// messageResolver hands message to destination chain
+type messageResolver interface {
+	ResolveMessage(m *utils.Message) bool
+}
+
+// depositsFetcher rebuilds messages of deposits made in block range
+type depositsFetcher interface {
+	FetchDepositsInRange(from, to *big.Int, nonce *utils.Nonce) ([]*utils.Message, error)
+}
+
+// Replay rebuilds messages of deposits made on source chain between --from and --to blocks and hands them to
+// destination writers once. Relayer using --leveldb should be stopped, as replay shares its validators, voted proposals,
+// circuit breakers and approval queues. Replay only votes, it returns without waiting for proposals to pass, so they are
+// executed by relayers once started. Blockstore checkpoints are not touched. With --dry-run messages are printed as JSON
+// instead
+func Replay(ctx *cli.Context) error {
+	if !ctx.IsSet(flags.ChainIDFlag.Name) || !ctx.IsSet(flags.FromBlockFlag.Name) || !ctx.IsSet(flags.ToBlockFlag.Name) {
+		return errors.New("--chain, --from and --to should be provided")
+	}
+	from := new(big.Int).SetUint64(ctx.Uint64(flags.FromBlockFlag.Name))
+	to := new(big.Int).SetUint64(ctx.Uint64(flags.ToBlockFlag.Name))
+	if from.Cmp(to) > 0 {
+		return fmt.Errorf("from block %s is after to block %s", from, to)
+	}
+	var nonce *utils.Nonce
+	if ctx.IsSet(flags.DepositNonceFlag.Name) {
+		n := utils.Nonce(ctx.Uint64(flags.DepositNonceFlag.Name))
+		nonce = &n
+	}
+	startConfig, err := cfg.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	configs := make(map[utils.ChainId]*config.CeloChainConfig)
+	for _, c := range startConfig.Chains {
+		celoChainConfig, err := config.ParseChainConfig(&c, ctx)
+		if err != nil {
+			return err
+		}
+		configs[celoChainConfig.ID] = celoChainConfig
+	}
+	sourceConfig, ok := configs[utils.ChainId(ctx.Uint(flags.ChainIDFlag.Name))]
+	if !ok {
+		return fmt.Errorf("chain %d is not configured", ctx.Uint(flags.ChainIDFlag.Name))
+	}
+
+	ldb, err := openLevelDB(ctx)
+	if err != nil {
+		return err
+	}
+	validatorsStore := validatorsync.NewValidatorsStore(ldb)
+	defer validatorsStore.Close()
+	err = validatorsStore.Migrate()
+	if err != nil {
+		return err
+	}
+	stopChn := make(chan struct{})
+	defer close(stopChn)
+	errChn := make(chan error, 1)
+
+	sourceClient, err := client.NewClient(sourceConfig.Endpoint, sourceConfig.Http, nil, sourceConfig.GasLimit, sourceConfig.MaxGasPrice, sourceConfig.GasMultiplier)
+	if err != nil {
+		return err
+	}
+	defer sourceClient.Close()
+	err = sourceConfig.ResolveEpochSize(sourceClient)
+	if err != nil {
+		return err
+	}
+	l, err := newReplayListener(sourceConfig, sourceClient, validatorsStore, stopChn, errChn)
+	if err != nil {
+		return err
+	}
+	// Validators of replayed blocks may be missing when relayer database is behind
+	go validatorsync.SyncBlockValidators(stopChn, errChn, sourceClient, validatorsStore, uint8(sourceConfig.ID), sourceConfig.EpochSize, sourceConfig.ValidatorsSyncWorkers)
+
+	msgs, err := l.FetchDepositsInRange(from, to, nonce)
+	if err != nil {
+		return err
+	}
+	log.Info().Interface("chain", sourceConfig.ID).Str("from", from.String()).Str("to", to.String()).Int("deposits", len(msgs)).Msg("Rebuilt deposits")
+	if ctx.Bool(flags.DryRunFlag.Name) {
+		enc := json.NewEncoder(os.Stdout)
+		for _, m := range msgs {
+			err = enc.Encode(m)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	writers := make(map[utils.ChainId]messageResolver)
+	for _, m := range msgs {
+		w, ok := writers[m.Destination]
+		if !ok {
+			destConfig, ok := configs[m.Destination]
+			if !ok {
+				log.Error().Interface("dest", m.Destination).Interface("nonce", m.DepositNonce).Msg("Destination chain is not configured, skipping deposit")
+				continue
+			}
+			w, err = newReplayWriter(destConfig, ldb, stopChn, errChn)
+			if err != nil {
+				return err
+			}
+			writers[m.Destination] = w
+		}
+		if !w.ResolveMessage(m) {
+			log.Error().Interface("dest", m.Destination).Interface("nonce", m.DepositNonce).Msg("Failed to resolve replayed deposit")
+		}
+	}
+	log.Info().Msg("Replay voted on deposits and does not wait for execution, passed proposals are executed by relayers once started")
+	return nil
+}
+
+// newReplayListener creates listener of source chain that is used only to rebuild deposits
+func newReplayListener(celoChainConfig *config.CeloChainConfig, c *client.Client, valsAggr listener.ValidatorsAggregator, stopChn <-chan struct{}, errChn chan<- error) (depositsFetcher, error) {
+	bridgeContract, err := bridgeHandler.NewBridge(celoChainConfig.BridgeContract, c)
+	if err != nil {
+		return nil, err
+	}
+	erc20HandlerContract, err := erc20Handler.NewERC20Handler(celoChainConfig.Erc20HandlerContract, c)
+	if err != nil {
+		return nil, err
+	}
+	erc721HandlerContract, err := erc721Handler.NewERC721Handler(celoChainConfig.Erc721HandlerContract, c)
+	if err != nil {
+		return nil, err
+	}
+	genericHandlerContract, err := GenericHandler.NewGenericHandler(celoChainConfig.GenericHandlerContract, c)
+	if err != nil {
+		return nil, err
+	}
+	l := listener.NewListener(celoChainConfig, c, nil, stopChn, errChn, nil, valsAggr)
+	l.SetContracts(bridgeContract, erc20HandlerContract, erc721HandlerContract, genericHandlerContract)
+	return l, nil
+}
+
+// newReplayWriter creates writer of destination chain signing with relayer key. Volume limits and approval thresholds
+// of the chain are enforced as by running relayer, replay fails if a circuit breaker of the chain is already tripped.
+// Voted proposals are persisted so relayer cancels them once expired
+func newReplayWriter(celoChainConfig *config.CeloChainConfig, ldb *leveldb.DB, stopChn <-chan struct{}, errChn chan<- error) (messageResolver, error) {
+	kpI, err := keystore.KeypairFromAddress(celoChainConfig.From, keystore.EthChain, celoChainConfig.KeystorePath, celoChainConfig.Insecure)
+	if err != nil {
+		return nil, err
+	}
+	kp, _ := kpI.(*secp256k1.Keypair)
+	c, err := client.NewClient(celoChainConfig.Endpoint, celoChainConfig.Http, kp, celoChainConfig.GasLimit, celoChainConfig.MaxGasPrice, celoChainConfig.GasMultiplier)
+	if err != nil {
+		return nil, err
+	}
+	bridgeContract, err := bridgeHandler.NewBridge(celoChainConfig.BridgeContract, c)
+	if err != nil {
+		return nil, err
+	}
+	// Writer is stopped on its own once a circuit breaker trips, as operator can not release it while replay holds
+	// relayer database and writer would wait for release forever
+	writerStop := make(chan struct{})
+	var once sync.Once
+	stopWriter := func() {
+		once.Do(func() { close(writerStop) })
+	}
+	go func() {
+		<-stopChn
+		stopWriter()
+	}()
+	w := writer.NewWriter(c, celoChainConfig, writerStop, errChn, nil)
+	w.SetBridge(bridgeContract)
+	err = w.SetProposalStore(proposalstore.NewStore(ldb, celoChainConfig.ID))
+	if err != nil {
+		return nil, err
+	}
+	if len(celoChainConfig.VolumeLimits) > 0 {
+		l, err := limiter.NewLimiter(ldb, celoChainConfig.ID, celoChainConfig.VolumeLimits, func() error {
+			stopWriter()
+			return fmt.Errorf("circuit breaker tripped during replay, remaining deposits to chain %d are not replayed", celoChainConfig.ID)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if tripped := l.Tripped(); len(tripped) > 0 {
+			return nil, fmt.Errorf("circuit breaker of resource %s on chain %d is tripped (%s), start relayer with --adminAddr, release it with `chainbridge-celo limits release` and stop relayer before replay", tripped[0].ResourceID.Hex(), celoChainConfig.ID, tripped[0].Reason)
+		}
+		w.SetLimiter(l)
+	}
+	if len(celoChainConfig.ApprovalThresholds) > 0 {
+		q := approval.NewQueue(ldb, celoChainConfig.ID, celoChainConfig.ApprovalThresholds)
+		q.SetResolver(w.ResolveApprovedMessage)
+		w.SetApprovalQueue(q)
+	}
+	return w, nil
+}
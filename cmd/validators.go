@@ -0,0 +1,193 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+
+	"github.com/ChainSafe/chainbridge-celo/chain/client"
+	"github.com/ChainSafe/chainbridge-celo/chain/config"
+	"github.com/ChainSafe/chainbridge-celo/flags"
+	"github.com/ChainSafe/chainbridge-celo/validatorsync"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// openValidatorsStore opens validators store of stopped relayer and migrates it to current schema
+func openValidatorsStore(ctx *cli.Context) (*validatorsync.ValidatorsStore, error) {
+	ldb, err := openLevelDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	store := validatorsync.NewValidatorsStore(ldb)
+	err = store.Migrate()
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// ListValidatorEpochs prints synced epochs of every chain or of chain provided with flag
+func ListValidatorEpochs(ctx *cli.Context) error {
+	store, err := openValidatorsStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	chains := []uint8{uint8(ctx.Uint(flags.ChainIDFlag.Name))}
+	if !ctx.IsSet(flags.ChainIDFlag.Name) {
+		chains, err = store.Chains()
+		if err != nil {
+			return err
+		}
+	}
+	for _, chainID := range chains {
+		epochs, err := store.Epochs(chainID)
+		if err != nil {
+			return err
+		}
+		if len(epochs) == 0 {
+			fmt.Printf("chain: %d no epochs synced\n", chainID)
+			continue
+		}
+		fmt.Printf("chain: %d epochs: %d first: %s latest: %s\n", chainID, len(epochs), epochs[0], epochs[len(epochs)-1])
+		for _, block := range epochs {
+			vals, err := store.GetValidatorsForBlock(block, chainID)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("  block: %s validators: %d\n", block, len(vals))
+		}
+	}
+	return nil
+}
+
+// ShowValidators prints validators and aggregated public key stored for block. With epoch size provided
+// any block may be used, otherwise block should be last block of epoch
+func ShowValidators(ctx *cli.Context) error {
+	store, err := openValidatorsStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	chainID := uint8(ctx.Uint(flags.ChainIDFlag.Name))
+	block := ctx.Uint64(flags.BlockFlag.Name)
+	if epochSize := ctx.Uint64(flags.EpochSizeFlag.Name); epochSize != 0 {
+		block = istanbul.GetEpochLastBlockNumber(istanbul.GetEpochNumber(block, epochSize), epochSize)
+	}
+	epoch, err := store.GetEpoch(new(big.Int).SetUint64(block), chainID)
+	if errors.Is(err, validatorsync.ErrEpochNotFound) {
+		return fmt.Errorf("validators of chain %d block %d are not stored", chainID, block)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("chain: %d block: %s validators: %d\n", chainID, epoch.Block, len(epoch.Validators))
+	fmt.Printf("apk: %s\n", hexutil.Encode(epoch.APK))
+	for _, v := range epoch.Validators {
+		fmt.Printf("  %s bls: %s\n", v.Address.Hex(), hexutil.Encode(v.BLSPublicKey[:]))
+	}
+	return nil
+}
+
+// VerifyValidators checks continuity and aggregated public keys of stored epochs
+func VerifyValidators(ctx *cli.Context) error {
+	store, err := openValidatorsStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	chainID := uint8(ctx.Uint(flags.ChainIDFlag.Name))
+	problems, err := store.Verify(chainID, ctx.Uint64(flags.EpochSizeFlag.Name))
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("validators store of chain %d has %d problems", chainID, len(problems))
+	}
+	log.Info().Uint8("chain", chainID).Msg("Validators store is consistent")
+	return nil
+}
+
+// ExportValidators writes JSON snapshot of stored epochs to file or stdout
+func ExportValidators(ctx *cli.Context) error {
+	store, err := openValidatorsStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	snapshot, err := store.Export(uint8(ctx.Uint(flags.ChainIDFlag.Name)), ctx.Uint64(flags.EpochSizeFlag.Name))
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := ctx.String(flags.FileFlag.Name)
+	if path == "" {
+		_, err = os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+	err = ioutil.WriteFile(path, data, 0600)
+	if err != nil {
+		return err
+	}
+	log.Info().Uint8("chain", snapshot.ChainID).Int("epochs", len(snapshot.Epochs)).Str("file", path).Msg("Validators exported")
+	return nil
+}
+
+// ImportValidators stores epochs of JSON snapshot file after verifying it against configured chain
+func ImportValidators(ctx *cli.Context) error {
+	data, err := ioutil.ReadFile(ctx.String(flags.FileFlag.Name))
+	if err != nil {
+		return err
+	}
+	snapshot := &validatorsync.Snapshot{}
+	err = json.Unmarshal(data, snapshot)
+	if err != nil {
+		return fmt.Errorf("unable to parse snapshot: %w", err)
+	}
+	// Snapshot is verified against epoch headers of configured chain
+	var celoChainConfig *config.CeloChainConfig
+	configs, err := chainConfigs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range configs {
+		if uint8(c.ID) == snapshot.ChainID {
+			celoChainConfig = c
+		}
+	}
+	if celoChainConfig == nil {
+		return fmt.Errorf("chain %d of snapshot is not configured", snapshot.ChainID)
+	}
+	chainClient, err := client.NewClient(celoChainConfig.Endpoint, celoChainConfig.Http, nil, celoChainConfig.GasLimit, celoChainConfig.MaxGasPrice, celoChainConfig.GasMultiplier)
+	if err != nil {
+		return err
+	}
+	defer chainClient.Close()
+	store, err := openValidatorsStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	err = store.Import(chainClient, snapshot)
+	if err != nil {
+		return err
+	}
+	log.Info().Uint8("chain", snapshot.ChainID).Int("epochs", len(snapshot.Epochs)).Msg("Validators imported")
+	return nil
+}
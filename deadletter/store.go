@@ -0,0 +1,167 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package deadletter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	deadLetterKeyPrefix = "deadLetter"
+)
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetter is a deposit listener could not route to its destination together with context needed to retry it
+type DeadLetter struct {
+	Source      utils.ChainId    `json:"source"`
+	Destination utils.ChainId    `json:"destination"`
+	Nonce       utils.Nonce      `json:"depositNonce"`
+	ResourceID  utils.ResourceId `json:"resourceId"`
+	Block       uint64           `json:"block"`
+	BlockHash   common.Hash      `json:"blockHash"`
+	TxHash      common.Hash      `json:"txHash"`
+	TxIndex     uint             `json:"txIndex"`
+	Reason      string           `json:"reason"`            // Why deposit was not routed, eg. unrecognized handler or unknown destination
+	Message     *utils.Message   `json:"message,omitempty"` // Rebuilt message if deposit handler was recognized
+	RecordedAt  time.Time        `json:"recordedAt"`
+	Retries     int              `json:"retries"`
+}
+
+// Store is a persistent store of dead letters of deposits made on a single source chain
+type Store struct {
+	db      *leveldb.DB
+	chainID utils.ChainId
+	retry   func(dl *DeadLetter) error
+	lock    sync.Mutex
+}
+
+func NewStore(db *leveldb.DB, chainID utils.ChainId) *Store {
+	return &Store{
+		db:      db,
+		chainID: chainID,
+	}
+}
+
+// SetRetrier sets function that processes dead letter again
+func (s *Store) SetRetrier(retry func(dl *DeadLetter) error) {
+	s.retry = retry
+}
+
+func (s *Store) ChainID() utils.ChainId {
+	return s.chainID
+}
+
+// Record persists dead letter. Dead letter of the same deposit is replaced keeping its retries count
+func (s *Store) Record(dl *DeadLetter) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	existing, err := s.get(dl.Destination, dl.Nonce)
+	if err == nil {
+		dl.Retries = existing.Retries
+	} else if !errors.Is(err, ErrDeadLetterNotFound) {
+		return err
+	}
+	dl.Source = s.chainID
+	dl.RecordedAt = time.Now()
+	err = s.put(dl)
+	if err != nil {
+		return err
+	}
+	log.Error().Interface("src", dl.Source).Interface("dst", dl.Destination).Interface("nonce", dl.Nonce).Str("rId", dl.ResourceID.Hex()).Uint64("block", dl.Block).Str("reason", dl.Reason).Msg("Deposit recorded as dead letter")
+	return nil
+}
+
+// DeadLetters returns all dead letters of source chain
+func (s *Store) DeadLetters() ([]*DeadLetter, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	res := make([]*DeadLetter, 0)
+	iter := s.db.NewIterator(util.BytesPrefix(deadLetterPrefix(s.chainID)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		dl := &DeadLetter{}
+		err := json.Unmarshal(iter.Value(), dl)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, dl)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Retry hands dead letter to retrier. Dead letter is removed if retry succeeds, otherwise its reason is updated
+func (s *Store) Retry(dest utils.ChainId, nonce utils.Nonce) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	dl, err := s.get(dest, nonce)
+	if err != nil {
+		return err
+	}
+	if s.retry == nil {
+		return errors.New("dead letter store has no retrier")
+	}
+	err = s.retry(dl)
+	if err != nil {
+		dl.Retries++
+		dl.Reason = err.Error()
+		if putErr := s.put(dl); putErr != nil {
+			return putErr
+		}
+		return err
+	}
+	log.Info().Interface("src", dl.Source).Interface("dst", dest).Interface("nonce", nonce).Msg("Dead letter retried")
+	return s.db.Delete(deadLetterKey(s.chainID, dest, nonce), nil)
+}
+
+func (s *Store) get(dest utils.ChainId, nonce utils.Nonce) (*DeadLetter, error) {
+	data, err := s.db.Get(deadLetterKey(s.chainID, dest, nonce), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+	dl := &DeadLetter{}
+	err = json.Unmarshal(data, dl)
+	if err != nil {
+		return nil, err
+	}
+	return dl, nil
+}
+
+func (s *Store) put(dl *DeadLetter) error {
+	data, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(deadLetterKey(s.chainID, dl.Destination, dl.Nonce), data, nil)
+}
+
+func deadLetterPrefix(chainID utils.ChainId) []byte {
+	key := bytes.NewBufferString(deadLetterKeyPrefix)
+	key.WriteByte(uint8(chainID))
+	return key.Bytes()
+}
+
+func deadLetterKey(chainID utils.ChainId, dest utils.ChainId, nonce utils.Nonce) []byte {
+	key := bytes.NewBuffer(deadLetterPrefix(chainID))
+	key.WriteByte(uint8(dest))
+	_ = binary.Write(key, binary.BigEndian, uint64(nonce))
+	return key.Bytes()
+}
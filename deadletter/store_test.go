@@ -0,0 +1,91 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package deadletter
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/stretchr/testify/suite"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+type StoreTestSuite struct {
+	suite.Suite
+	db    *leveldb.DB
+	store *Store
+}
+
+func TestRunStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(StoreTestSuite))
+}
+
+func (s *StoreTestSuite) SetupSuite()    {}
+func (s *StoreTestSuite) TearDownSuite() {}
+func (s *StoreTestSuite) SetupTest() {
+	db, err := leveldb.OpenFile("./test/db", nil)
+	if err != nil {
+		s.Fail(err.Error())
+	}
+	s.db = db
+	s.store = NewStore(db, 2)
+}
+func (s *StoreTestSuite) TearDownTest() {
+	s.db.Close()
+	os.RemoveAll("./test")
+}
+
+func (s *StoreTestSuite) TestRecordAndList() {
+	s.Nil(s.store.Record(&DeadLetter{Destination: 1, Nonce: 5, Block: 10, Reason: "unrecognized handler"}))
+	s.Nil(s.store.Record(&DeadLetter{Destination: 3, Nonce: 1, Block: 11, Reason: "unknown destination chainId: 3"}))
+	// Dead letters of other source chains are not listed
+	s.Nil(NewStore(s.db, 3).Record(&DeadLetter{Destination: 1, Nonce: 5}))
+
+	dls, err := s.store.DeadLetters()
+	s.Nil(err)
+	s.Len(dls, 2)
+	s.Equal(utils.ChainId(2), dls[0].Source)
+	s.Equal(utils.Nonce(5), dls[0].Nonce)
+	s.Equal("unrecognized handler", dls[0].Reason)
+	s.False(dls[0].RecordedAt.IsZero())
+	s.Equal(utils.ChainId(3), dls[1].Destination)
+}
+
+func (s *StoreTestSuite) TestRetrySucceeds() {
+	s.Nil(s.store.Record(&DeadLetter{Destination: 1, Nonce: 5, Block: 10}))
+	var retried *DeadLetter
+	s.store.SetRetrier(func(dl *DeadLetter) error { retried = dl; return nil })
+
+	s.Nil(s.store.Retry(1, 5))
+	s.Equal(uint64(10), retried.Block)
+	dls, err := s.store.DeadLetters()
+	s.Nil(err)
+	s.Len(dls, 0)
+	s.True(errors.Is(s.store.Retry(1, 5), ErrDeadLetterNotFound))
+}
+
+func (s *StoreTestSuite) TestRetryFails() {
+	s.Nil(s.store.Record(&DeadLetter{Destination: 1, Nonce: 5, Reason: "unrecognized handler"}))
+	s.store.SetRetrier(func(dl *DeadLetter) error { return errors.New("still unrecognized") })
+
+	s.NotNil(s.store.Retry(1, 5))
+	dls, err := s.store.DeadLetters()
+	s.Nil(err)
+	s.Len(dls, 1)
+	s.Equal(1, dls[0].Retries)
+	s.Equal("still unrecognized", dls[0].Reason)
+
+	// Recording deposit again keeps retries count
+	s.Nil(s.store.Record(&DeadLetter{Destination: 1, Nonce: 5, Reason: "unrecognized handler"}))
+	dls, err = s.store.DeadLetters()
+	s.Nil(err)
+	s.Equal(1, dls[0].Retries)
+}
+
+func (s *StoreTestSuite) TestRetryWithoutRetrier() {
+	s.Nil(s.store.Record(&DeadLetter{Destination: 1, Nonce: 5}))
+	s.NotNil(s.store.Retry(1, 5))
+}
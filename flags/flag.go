@@ -36,7 +36,7 @@ var (
 
 	BlockstorePathFlag = &cli.StringFlag{
 		Name:  "blockstore",
-		Usage: "Specify path for blockstore",
+		Usage: "Specify path of legacy blockstore files migrated to LevelDB on first start",
 		Value: "", // Empty will use home dir
 	}
 
@@ -55,6 +55,133 @@ var (
 		Usage:    "sets path to leveldb database",
 		Required: true,
 	}
+
+	ObserverFlag = &cli.BoolFlag{
+		Name:  "observer",
+		Usage: "Runs relayer in non-voting observer mode that compares proposals it would vote for with on-chain activity. Keystore is not required",
+	}
+)
+
+// Admin API flags
+var (
+	AdminAddrFlag = &cli.StringFlag{
+		Name:  "adminAddr",
+		Usage: "Address for local admin API to listen on, eg. 127.0.0.1:8002. Admin API is disabled if empty, non loopback address requires --adminToken",
+	}
+
+	AdminTokenFlag = &cli.StringFlag{
+		Name:  "adminToken",
+		Usage: "Bearer token admin API requests should carry, required if admin API listens on non loopback address",
+	}
+
+	AdminURLFlag = &cli.StringFlag{
+		Name:  "adminUrl",
+		Usage: "URL of running relayer admin API",
+		Value: "http://127.0.0.1:8002",
+	}
+
+	ChainIDFlag = &cli.UintFlag{
+		Name:  "chain",
+		Usage: "Chain ID",
+	}
+
+	ResourceIDFlag = &cli.StringFlag{
+		Name:  "resource",
+		Usage: "Resource ID",
+	}
+
+	SourceIDFlag = &cli.UintFlag{
+		Name:  "source",
+		Usage: "Source chain ID of transfer",
+	}
+
+	DestIDFlag = &cli.UintFlag{
+		Name:  "dest",
+		Usage: "Destination chain ID of transfer",
+	}
+
+	DepositNonceFlag = &cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "Deposit nonce of transfer",
+	}
+
+	ReasonFlag = &cli.StringFlag{
+		Name:  "reason",
+		Usage: "Reason of operator decision",
+	}
+)
+
+// Validators store flags
+var (
+	BlockFlag = &cli.Uint64Flag{
+		Name:  "block",
+		Usage: "Block number",
+	}
+
+	EpochSizeFlag = &cli.Uint64Flag{
+		Name:  "epochSize",
+		Usage: "Size of chain epoch in blocks",
+	}
+
+	FileFlag = &cli.StringFlag{
+		Name:  "file",
+		Usage: "Path to snapshot file",
+	}
+)
+
+// Blockstore flags
+var (
+	RelayerFlag = &cli.StringFlag{
+		Name:  "relayer",
+		Usage: "Relayer address of blockstore checkpoint, defaults to chain from address or observer with --observer",
+	}
+
+	BlocksFlag = &cli.Uint64Flag{
+		Name:  "blocks",
+		Usage: "Number of blocks to rewind checkpoint by",
+	}
+
+	YesFlag = &cli.BoolFlag{
+		Name:  "yes",
+		Usage: "Skip confirmation",
+	}
+)
+
+// Replay flags
+var (
+	FromBlockFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block of replayed range",
+	}
+
+	ToBlockFlag = &cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block of replayed range",
+	}
+
+	DryRunFlag = &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print rebuilt messages as JSON instead of handing them to writers",
+	}
+)
+
+// Reconciliation flags
+var (
+	ReconcileIntervalFlag = &cli.DurationFlag{
+		Name:  "reconcileInterval",
+		Usage: "Interval of deposit nonces reconciliation of configured chains, eg. 1h. Reconciliation is disabled if 0",
+	}
+
+	FormatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "Report format, json or csv",
+		Value: "json",
+	}
+
+	OutputFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "Path to report file, report is printed to stdout if empty",
+	}
 )
 
 // Metrics flags
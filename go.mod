@@ -11,6 +11,7 @@ require (
 	github.com/celo-org/celo-bls-go v0.1.6
 	github.com/ethereum/go-ethereum v1.9.17
 	github.com/golang/mock v1.4.4
+	github.com/hashicorp/golang-lru v0.5.4
 	github.com/pkg/errors v0.9.1
 	github.com/rs/zerolog v1.20.0
 	github.com/stretchr/testify v1.7.0
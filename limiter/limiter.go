@@ -0,0 +1,284 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package limiter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/rs/zerolog/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	trippedKeyPrefix = "limiterTripped"
+)
+
+var ErrNotTripped = errors.New("circuit breaker for resource is not tripped")
+
+// Limit describes rolling window restrictions for a single resource id.
+type Limit struct {
+	ResourceID   utils.ResourceId
+	MaxAmount    *big.Int      // Max summary amount of fungible transfers inside window. Nil disables amount check
+	MaxTransfers uint64        // Max number of transfers inside window. Zero disables count check
+	Window       time.Duration // Length of rolling window
+}
+
+// TrippedResource is a resource that exceeded its limit and waits for an operator to release it
+type TrippedResource struct {
+	ChainID    utils.ChainId    `json:"chainId"`
+	ResourceID utils.ResourceId `json:"resourceId"`
+	Reason     string           `json:"reason"`
+	TrippedAt  time.Time        `json:"trippedAt"`
+}
+
+type record struct {
+	at     time.Time
+	amount *big.Int
+	source utils.ChainId
+	nonce  utils.Nonce
+}
+
+// Limiter enforces rolling window limits per resource id for messages destined to a single chain.
+// Tripped circuit breakers are persisted, so a restart does not release them. Window records are kept in memory only.
+type Limiter struct {
+	db       *leveldb.DB
+	chainID  utils.ChainId
+	limits   map[utils.ResourceId]*Limit
+	records  map[utils.ResourceId][]record
+	tripped  map[utils.ResourceId]*TrippedResource
+	released map[utils.ResourceId]chan struct{}
+	pause    func() error
+	lock     sync.Mutex
+	now      func() time.Time
+}
+
+// NewLimiter creates limiter for chainID and loads previously tripped circuit breakers from db.
+// If pause is not nil it is called every time a circuit breaker trips.
+func NewLimiter(db *leveldb.DB, chainID utils.ChainId, limits []*Limit, pause func() error) (*Limiter, error) {
+	l := &Limiter{
+		db:       db,
+		chainID:  chainID,
+		limits:   make(map[utils.ResourceId]*Limit),
+		records:  make(map[utils.ResourceId][]record),
+		tripped:  make(map[utils.ResourceId]*TrippedResource),
+		released: make(map[utils.ResourceId]chan struct{}),
+		pause:    pause,
+		now:      time.Now,
+	}
+	for _, limit := range limits {
+		l.limits[limit.ResourceID] = limit
+	}
+	iter := db.NewIterator(util.BytesPrefix(trippedPrefix(chainID)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		t := &TrippedResource{}
+		err := json.Unmarshal(iter.Value(), t)
+		if err != nil {
+			return nil, fmt.Errorf("decoding tripped resource: %w", err)
+		}
+		l.tripped[t.ResourceID] = t
+		l.released[t.ResourceID] = make(chan struct{})
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Check records message m in the rolling window of its resource. It returns false if the resource circuit breaker
+// is already tripped or if the message exceeds the resource limit, in which case the breaker is tripped.
+// Message of deposit already recorded in the window, eg. retried vote, is not counted again.
+func (l *Limiter) Check(m *utils.Message) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if _, ok := l.tripped[m.ResourceId]; ok {
+		return false
+	}
+	limit, ok := l.limits[m.ResourceId]
+	if !ok {
+		return true
+	}
+	now := l.now()
+	records := l.recordsInWindow(limit, now)
+	for _, r := range records {
+		if r.source == m.Source && r.nonce == m.DepositNonce {
+			return true
+		}
+	}
+	amount := messageAmount(m)
+	if limit.MaxTransfers != 0 && uint64(len(records))+1 > limit.MaxTransfers {
+		l.trip(m.ResourceId, fmt.Sprintf("transfers count exceeds %d per %s", limit.MaxTransfers, limit.Window), now)
+		return false
+	}
+	if limit.MaxAmount != nil {
+		total := new(big.Int).Set(amount)
+		for _, r := range records {
+			total.Add(total, r.amount)
+		}
+		if total.Cmp(limit.MaxAmount) == 1 {
+			l.trip(m.ResourceId, fmt.Sprintf("transferred amount %s exceeds %s per %s", total, limit.MaxAmount, limit.Window), now)
+			return false
+		}
+	}
+	l.records[m.ResourceId] = append(records, record{at: now, amount: amount, source: m.Source, nonce: m.DepositNonce})
+	return true
+}
+
+// Released returns channel that is closed once the circuit breaker of resource rId is released
+func (l *Limiter) Released(rId utils.ResourceId) <-chan struct{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	ch, ok := l.released[rId]
+	if !ok {
+		ch = make(chan struct{})
+		close(ch)
+	}
+	return ch
+}
+
+// Release resets the circuit breaker and the rolling window of resource rId
+func (l *Limiter) Release(rId utils.ResourceId) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if _, ok := l.tripped[rId]; !ok {
+		return ErrNotTripped
+	}
+	err := l.db.Delete(trippedKey(l.chainID, rId), nil)
+	if err != nil {
+		return err
+	}
+	delete(l.tripped, rId)
+	delete(l.records, rId)
+	close(l.released[rId])
+	delete(l.released, rId)
+	log.Info().Interface("chain", l.chainID).Str("rId", rId.Hex()).Msg("Circuit breaker released")
+	return nil
+}
+
+// Tripped returns all resources that are waiting for operator release
+func (l *Limiter) Tripped() []*TrippedResource {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	res := make([]*TrippedResource, 0, len(l.tripped))
+	for _, t := range l.tripped {
+		res = append(res, t)
+	}
+	return res
+}
+
+func (l *Limiter) ChainID() utils.ChainId {
+	return l.chainID
+}
+
+func (l *Limiter) recordsInWindow(limit *Limit, now time.Time) []record {
+	records := l.records[limit.ResourceID]
+	i := 0
+	for ; i < len(records); i++ {
+		if now.Sub(records[i].at) < limit.Window {
+			break
+		}
+	}
+	return records[i:]
+}
+
+// trip should be called under lock
+func (l *Limiter) trip(rId utils.ResourceId, reason string, now time.Time) {
+	t := &TrippedResource{ChainID: l.chainID, ResourceID: rId, Reason: reason, TrippedAt: now}
+	l.tripped[rId] = t
+	l.released[rId] = make(chan struct{})
+	log.Error().Bool("alert", true).Interface("chain", l.chainID).Str("rId", rId.Hex()).Str("reason", reason).Msg("Volume limit exceeded, circuit breaker tripped. Voting on resource stopped until operator release")
+	data, err := json.Marshal(t)
+	if err == nil {
+		err = l.db.Put(trippedKey(l.chainID, rId), data, nil)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("rId", rId.Hex()).Msg("Failed to persist tripped circuit breaker")
+	}
+	if l.pause != nil {
+		go func() {
+			err := l.pause()
+			if err != nil {
+				log.Error().Err(err).Interface("chain", l.chainID).Msg("Failed to pause bridge transfers")
+				return
+			}
+			log.Warn().Interface("chain", l.chainID).Msg("Bridge transfers paused by circuit breaker")
+		}()
+	}
+}
+
+func messageAmount(m *utils.Message) *big.Int {
+	if m.Type != utils.FungibleTransfer || len(m.Payload) == 0 {
+		return big.NewInt(0)
+	}
+	amount, ok := m.Payload[0].([]byte)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return new(big.Int).SetBytes(amount)
+}
+
+func trippedPrefix(chainID utils.ChainId) []byte {
+	key := bytes.NewBufferString(trippedKeyPrefix)
+	key.WriteByte(uint8(chainID))
+	return key.Bytes()
+}
+
+func trippedKey(chainID utils.ChainId, rId utils.ResourceId) []byte {
+	return append(trippedPrefix(chainID), rId[:]...)
+}
+
+// ParseLimits parses limits from comma separated list of `resourceID:maxAmount:maxTransfers:window` entries.
+// Empty maxAmount or maxTransfers disables corresponding check, window is a duration string eg. `1h`.
+func ParseLimits(raw string) ([]*Limit, error) {
+	limits := make([]*Limit, 0)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		fields := strings.Split(entry, ":")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("malformed limit %s, expected resourceID:maxAmount:maxTransfers:window", entry)
+		}
+		limit := &Limit{}
+		rId, err := hexutil.Decode(fields[0])
+		if err != nil || len(rId) != 32 {
+			return nil, fmt.Errorf("invalid resource id %s", fields[0])
+		}
+		copy(limit.ResourceID[:], rId)
+		if fields[1] != "" {
+			amount, ok := new(big.Int).SetString(fields[1], 10)
+			if !ok {
+				return nil, fmt.Errorf("unable to parse max amount %s", fields[1])
+			}
+			limit.MaxAmount = amount
+		}
+		if fields[2] != "" {
+			limit.MaxTransfers, err = strconv.ParseUint(fields[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse max transfers %s: %w", fields[2], err)
+			}
+		}
+		limit.Window, err = time.ParseDuration(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse window %s: %w", fields[3], err)
+		}
+		if limit.MaxAmount == nil && limit.MaxTransfers == 0 {
+			return nil, fmt.Errorf("limit for resource %s has neither max amount nor max transfers", fields[0])
+		}
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
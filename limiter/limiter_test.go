@@ -0,0 +1,137 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package limiter
+
+import (
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/stretchr/testify/suite"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+type LimiterTestSuite struct {
+	suite.Suite
+	db    *leveldb.DB
+	rId   utils.ResourceId
+	nonce utils.Nonce
+}
+
+func TestRunLimiterTestSuite(t *testing.T) {
+	suite.Run(t, new(LimiterTestSuite))
+}
+
+func (s *LimiterTestSuite) SetupSuite()    {}
+func (s *LimiterTestSuite) TearDownSuite() {}
+func (s *LimiterTestSuite) SetupTest() {
+	db, err := leveldb.OpenFile("./test/db", nil)
+	if err != nil {
+		s.Fail(err.Error())
+	}
+	s.db = db
+	s.rId = utils.ResourceId{1}
+}
+func (s *LimiterTestSuite) TearDownTest() {
+	s.db.Close()
+	os.RemoveAll("./test")
+}
+
+// newMessage creates transfer of amount with next deposit nonce
+func (s *LimiterTestSuite) newMessage(amount int64) *utils.Message {
+	s.nonce++
+	return utils.NewFungibleTransfer(1, 2, s.nonce, s.rId, nil, nil, big.NewInt(amount), []byte{1})
+}
+
+func (s *LimiterTestSuite) TestTransfersCountExceeded() {
+	l, err := NewLimiter(s.db, 2, []*Limit{{ResourceID: s.rId, MaxTransfers: 2, Window: time.Hour}}, nil)
+	s.Nil(err)
+	s.True(l.Check(s.newMessage(1)))
+	s.True(l.Check(s.newMessage(1)))
+	s.False(l.Check(s.newMessage(1)))
+	s.Equal(1, len(l.Tripped()))
+	// Other resources are not affected
+	m := s.newMessage(1)
+	m.ResourceId = utils.ResourceId{2}
+	s.True(l.Check(m))
+}
+
+func (s *LimiterTestSuite) TestAmountExceeded() {
+	l, err := NewLimiter(s.db, 2, []*Limit{{ResourceID: s.rId, MaxAmount: big.NewInt(100), Window: time.Hour}}, nil)
+	s.Nil(err)
+	s.True(l.Check(s.newMessage(60)))
+	s.True(l.Check(s.newMessage(40)))
+	s.False(l.Check(s.newMessage(1)))
+	// Once tripped every message is rejected until release
+	s.False(l.Check(s.newMessage(0)))
+}
+
+func (s *LimiterTestSuite) TestRetriedTransferCountedOnce() {
+	l, err := NewLimiter(s.db, 2, []*Limit{{ResourceID: s.rId, MaxAmount: big.NewInt(100), MaxTransfers: 2, Window: time.Hour}}, nil)
+	s.Nil(err)
+	m := s.newMessage(60)
+	s.True(l.Check(m))
+	s.True(l.Check(m))
+	s.True(l.Check(m))
+	s.True(l.Check(s.newMessage(40)))
+	s.Equal(0, len(l.Tripped()))
+}
+
+func (s *LimiterTestSuite) TestRollingWindow() {
+	l, err := NewLimiter(s.db, 2, []*Limit{{ResourceID: s.rId, MaxTransfers: 1, Window: time.Hour}}, nil)
+	s.Nil(err)
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	s.True(l.Check(s.newMessage(1)))
+	now = now.Add(time.Hour)
+	s.True(l.Check(s.newMessage(1)))
+}
+
+func (s *LimiterTestSuite) TestReleaseAndPersistence() {
+	paused := make(chan struct{})
+	l, err := NewLimiter(s.db, 2, []*Limit{{ResourceID: s.rId, MaxTransfers: 1, Window: time.Hour}}, func() error { close(paused); return nil })
+	s.Nil(err)
+	s.True(l.Check(s.newMessage(1)))
+	s.False(l.Check(s.newMessage(1)))
+	select {
+	case <-paused:
+	case <-time.After(time.Second):
+		s.Fail("pause was not called")
+	}
+
+	// Restarted limiter should keep breaker tripped
+	restarted, err := NewLimiter(s.db, 2, []*Limit{{ResourceID: s.rId, MaxTransfers: 1, Window: time.Hour}}, nil)
+	s.Nil(err)
+	s.Equal(1, len(restarted.Tripped()))
+	released := restarted.Released(s.rId)
+	s.False(restarted.Check(s.newMessage(1)))
+
+	s.Nil(restarted.Release(s.rId))
+	select {
+	case <-released:
+	default:
+		s.Fail("released channel should be closed")
+	}
+	s.True(restarted.Check(s.newMessage(1)))
+	s.Equal(ErrNotTripped, restarted.Release(s.rId))
+}
+
+func (s *LimiterTestSuite) TestParseLimits() {
+	limits, err := ParseLimits("0x0100000000000000000000000000000000000000000000000000000000000000:1000::1h, 0x0200000000000000000000000000000000000000000000000000000000000000::5:30m")
+	s.Nil(err)
+	s.Equal(2, len(limits))
+	s.Equal(s.rId, limits[0].ResourceID)
+	s.Equal(big.NewInt(1000), limits[0].MaxAmount)
+	s.Equal(uint64(0), limits[0].MaxTransfers)
+	s.Equal(time.Hour, limits[0].Window)
+	s.Nil(limits[1].MaxAmount)
+	s.Equal(uint64(5), limits[1].MaxTransfers)
+
+	_, err = ParseLimits("0x01:1000::1h")
+	s.NotNil(err)
+	_, err = ParseLimits("0x0100000000000000000000000000000000000000000000000000000000000000:::1h")
+	s.NotNil(err)
+}
@@ -26,6 +26,10 @@ var cliFlags = []cli.Flag{
 	flags.MetricsPort,
 	flags.LevelDBPath,
 	flags.TestKeyFlag,
+	flags.AdminAddrFlag,
+	flags.AdminTokenFlag,
+	flags.ObserverFlag,
+	flags.ReconcileIntervalFlag,
 }
 
 //
@@ -78,6 +82,181 @@ var accountCommand = &cli.Command{
 	},
 }
 
+var limitsCommand = &cli.Command{
+	Name:  "limits",
+	Usage: "manage volume limits circuit breakers of running relayer",
+	Description: "The limits command is used to inspect and release tripped circuit breakers through relayer admin API.\n" +
+		"\tTo list tripped resources: chainbridge-celo limits list\n" +
+		"\tTo release resource: chainbridge-celo limits release --chain 1 --resource 0x...",
+	Subcommands: []*cli.Command{
+		{
+			Action: cmd.ListTrippedLimits,
+			Name:   "list",
+			Usage:  "list tripped circuit breakers",
+			Flags:  []cli.Flag{flags.AdminURLFlag, flags.AdminTokenFlag},
+		},
+		{
+			Action: cmd.ReleaseLimit,
+			Name:   "release",
+			Usage:  "release tripped circuit breaker of resource",
+			Flags:  []cli.Flag{flags.AdminURLFlag, flags.AdminTokenFlag, flags.ChainIDFlag, flags.ResourceIDFlag},
+		},
+	},
+}
+
+var approvalFlags = []cli.Flag{
+	flags.AdminURLFlag,
+	flags.AdminTokenFlag,
+	flags.ChainIDFlag,
+	flags.SourceIDFlag,
+	flags.DepositNonceFlag,
+}
+
+var approvalsCommand = &cli.Command{
+	Name:  "approvals",
+	Usage: "manage transfers waiting for manual approval in running relayer",
+	Description: "The approvals command is used to review transfers above approval threshold through relayer admin API.\n" +
+		"\tTo list parked transfers: chainbridge-celo approvals list\n" +
+		"\tTo approve transfer: chainbridge-celo approvals approve --chain 2 --source 1 --nonce 5\n" +
+		"\tTo reject transfer: chainbridge-celo approvals reject --chain 2 --source 1 --nonce 5 --reason \"suspicious\"",
+	Subcommands: []*cli.Command{
+		{
+			Action: cmd.ListApprovals,
+			Name:   "list",
+			Usage:  "list transfers in approval queue",
+			Flags:  []cli.Flag{flags.AdminURLFlag, flags.AdminTokenFlag},
+		},
+		{
+			Action: cmd.ApproveTransfer,
+			Name:   "approve",
+			Usage:  "approve parked transfer",
+			Flags:  approvalFlags,
+		},
+		{
+			Action: cmd.RejectTransfer,
+			Name:   "reject",
+			Usage:  "reject parked transfer",
+			Flags:  append(approvalFlags, flags.ReasonFlag),
+		},
+	},
+}
+
+var deadLettersCommand = &cli.Command{
+	Name:  "deadletters",
+	Usage: "manage deposits running relayer was unable to route",
+	Description: "The deadletters command is used to review deposits recorded as dead letters through relayer admin API.\n" +
+		"\tTo list dead letters: chainbridge-celo deadletters list\n" +
+		"\tTo retry dead letter once config is fixed: chainbridge-celo deadletters retry --chain 1 --dest 2 --nonce 5",
+	Subcommands: []*cli.Command{
+		{
+			Action: cmd.ListDeadLetters,
+			Name:   "list",
+			Usage:  "list dead letters of all source chains",
+			Flags:  []cli.Flag{flags.AdminURLFlag, flags.AdminTokenFlag},
+		},
+		{
+			Action: cmd.RetryDeadLetter,
+			Name:   "retry",
+			Usage:  "rebuild and route dead letter again",
+			Flags:  []cli.Flag{flags.AdminURLFlag, flags.AdminTokenFlag, flags.ChainIDFlag, flags.DestIDFlag, flags.DepositNonceFlag},
+		},
+	},
+}
+
+var validatorsCommand = &cli.Command{
+	Name:  "validators",
+	Usage: "inspect synced validators store of stopped relayer",
+	Description: "The validators command is used to inspect, verify, export and import validators synced from epoch headers.\n" +
+		"\tTo list synced epochs: chainbridge-celo validators list --leveldb ./lvldbdata\n" +
+		"\tTo show validators of block: chainbridge-celo validators show --leveldb ./lvldbdata --chain 1 --block 1000 --epochSize 17280\n" +
+		"\tTo verify store: chainbridge-celo validators verify --leveldb ./lvldbdata --chain 1 --epochSize 17280\n" +
+		"\tTo export snapshot: chainbridge-celo validators export --leveldb ./lvldbdata --chain 1 --epochSize 17280 --file snapshot.json\n" +
+		"\tTo import snapshot: chainbridge-celo validators import --config config.json --leveldb ./lvldbdata --file snapshot.json",
+	Subcommands: []*cli.Command{
+		{
+			Action: cmd.ListValidatorEpochs,
+			Name:   "list",
+			Usage:  "list synced epochs per chain",
+			Flags:  []cli.Flag{flags.LevelDBPath, flags.ChainIDFlag},
+		},
+		{
+			Action: cmd.ShowValidators,
+			Name:   "show",
+			Usage:  "show validators and aggregated public key of block",
+			Flags:  []cli.Flag{flags.LevelDBPath, flags.ChainIDFlag, flags.BlockFlag, flags.EpochSizeFlag},
+		},
+		{
+			Action: cmd.VerifyValidators,
+			Name:   "verify",
+			Usage:  "verify continuity of synced epochs",
+			Flags:  []cli.Flag{flags.LevelDBPath, flags.ChainIDFlag, flags.EpochSizeFlag},
+		},
+		{
+			Action: cmd.ExportValidators,
+			Name:   "export",
+			Usage:  "export synced epochs as JSON snapshot",
+			Flags:  []cli.Flag{flags.LevelDBPath, flags.ChainIDFlag, flags.EpochSizeFlag, flags.FileFlag},
+		},
+		{
+			Action: cmd.ImportValidators,
+			Name:   "import",
+			Usage:  "import JSON snapshot of synced epochs verified against chain",
+			Flags:  []cli.Flag{flags.ConfigFileFlag, flags.LevelDBPath, flags.FileFlag},
+		},
+	},
+}
+
+var blockstoreCommand = &cli.Command{
+	Name:  "blockstore",
+	Usage: "manage block checkpoints of stopped relayer",
+	Description: "The blockstore command is used to inspect and move block checkpoints listeners continue from.\n" +
+		"\tTo show checkpoints against chain heads: chainbridge-celo blockstore show --config config.json --leveldb ./lvldbdata\n" +
+		"\tTo set checkpoint: chainbridge-celo blockstore set --config config.json --leveldb ./lvldbdata --chain 1 --block 1000\n" +
+		"\tTo rewind checkpoint: chainbridge-celo blockstore rewind --config config.json --leveldb ./lvldbdata --chain 1 --blocks 100",
+	Subcommands: []*cli.Command{
+		{
+			Action: cmd.ShowBlockstore,
+			Name:   "show",
+			Usage:  "show checkpoints of configured chains against chain head",
+			Flags:  []cli.Flag{flags.ConfigFileFlag, flags.LevelDBPath, flags.ChainIDFlag},
+		},
+		{
+			Action: cmd.SetBlockstore,
+			Name:   "set",
+			Usage:  "set checkpoint of relayer",
+			Flags:  []cli.Flag{flags.ConfigFileFlag, flags.LevelDBPath, flags.ChainIDFlag, flags.BlockFlag, flags.RelayerFlag, flags.ObserverFlag, flags.YesFlag},
+		},
+		{
+			Action: cmd.RewindBlockstore,
+			Name:   "rewind",
+			Usage:  "move checkpoint of relayer back by number of blocks",
+			Flags:  []cli.Flag{flags.ConfigFileFlag, flags.LevelDBPath, flags.ChainIDFlag, flags.BlocksFlag, flags.RelayerFlag, flags.ObserverFlag, flags.YesFlag},
+		},
+	},
+}
+
+var replayCommand = &cli.Command{
+	Name:   "replay",
+	Action: cmd.Replay,
+	Usage:  "rebuild deposits of block range and relay them once",
+	Description: "The replay command rebuilds messages of deposits made on source chain in block range with proofs and hands them to destination chains once.\n" +
+		"\tBlockstore checkpoints are not changed. Relayer using LevelDB must be stopped, replay shares its validators, voted proposals, circuit breakers and approvals.\n" +
+		"\tTo print rebuilt messages: chainbridge-celo replay --config config.json --leveldb ./lvldbdata --chain 1 --from 1000 --to 2000 --dry-run\n" +
+		"\tTo relay deposit: chainbridge-celo replay --config config.json --leveldb ./lvldbdata --chain 1 --from 1000 --to 2000 --nonce 5",
+	Flags: []cli.Flag{flags.ConfigFileFlag, flags.LevelDBPath, flags.KeystorePathFlag, flags.TestKeyFlag, flags.ChainIDFlag, flags.FromBlockFlag, flags.ToBlockFlag, flags.DepositNonceFlag, flags.DryRunFlag},
+}
+
+var reconcileCommand = &cli.Command{
+	Name:   "reconcile",
+	Action: cmd.Reconcile,
+	Usage:  "report deposits that were not proposed or which proposals need attention",
+	Description: "The reconcile command compares deposit nonces of every configured chain pair with proposals on destination chains.\n" +
+		"\tIt reports deposits that were never proposed, proposals stuck in Active or Passed status and cancelled proposals.\n" +
+		"\tTo print JSON report: chainbridge-celo reconcile --config config.json\n" +
+		"\tTo write CSV report: chainbridge-celo reconcile --config config.json --format csv --output report.csv",
+	Flags: []cli.Flag{flags.ConfigFileFlag, flags.FormatFlag, flags.OutputFlag},
+}
+
 var deployerTestCommands = &cli.Command{
 	Name:   "deploy",
 	Action: e2e.Deploy,
@@ -105,6 +284,13 @@ func init() {
 		bridgeRun,
 		cbcli.CLICMD,
 		deployerTestCommands,
+		limitsCommand,
+		approvalsCommand,
+		deadLettersCommand,
+		validatorsCommand,
+		blockstoreCommand,
+		replayCommand,
+		reconcileCommand,
 	}
 }
 
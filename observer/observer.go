@@ -0,0 +1,282 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package observer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/bindings/Bridge"
+	"github.com/ChainSafe/chainbridge-celo/chain/client"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+)
+
+var BlockDelay = big.NewInt(1)
+var BlockRetryInterval = time.Second * 5
+var BlockRetryLimit = 5
+
+// Time after which data hash computed by observer is forgotten if its proposal was not executed or cancelled
+var ExpectedTimeout = time.Hour * 24
+
+var ErrFatalPolling = errors.New("observer block polling failed")
+
+const voteProposalMethod = "voteProposal"
+
+type ChainReader interface {
+	client.LogFilterWithLatestBlock
+	TransactionByHash(ctx context.Context, hash ethcommon.Hash) (*types.Transaction, bool, error)
+}
+
+type proposalKey struct {
+	source utils.ChainId
+	nonce  utils.Nonce
+}
+
+// expectation is a data hash computed by observer
+type expectation struct {
+	dataHash ethcommon.Hash
+	at       time.Time
+}
+
+// progress is a range of deposit nonces from a source chain that observer computed data hashes for since it started.
+// Deposits are processed in order, so every deposit in the range was already seen by the source listener
+type progress struct {
+	first utils.Nonce
+	last  utils.Nonce
+}
+
+// sighting is a data hash seen on-chain that is not yet compared with the one computed by observer
+type sighting struct {
+	dataHash ethcommon.Hash
+	block    *big.Int
+	tx       ethcommon.Hash
+}
+
+// Observer watches proposal activity of other relayers on a destination chain and compares it with
+// data hashes this relayer would have voted for. Any proposal which data hash observer can not reproduce
+// from the source chain is reported as divergence.
+type Observer struct {
+	chainID   utils.ChainId
+	bridge    ethcommon.Address
+	client    ChainReader
+	bridgeABI abi.ABI
+	stop      <-chan struct{}
+	sysErr    chan<- error
+	expected  map[proposalKey]*expectation
+	progress  map[utils.ChainId]*progress
+	sightings map[proposalKey][]*sighting
+	diverged  int // number of reported divergences
+	lock      sync.Mutex
+}
+
+func NewObserver(chainID utils.ChainId, bridge ethcommon.Address, client ChainReader, stop <-chan struct{}, sysErr chan<- error) (*Observer, error) {
+	bridgeABI, err := abi.JSON(strings.NewReader(Bridge.BridgeABI))
+	if err != nil {
+		return nil, err
+	}
+	return &Observer{
+		chainID:   chainID,
+		bridge:    bridge,
+		client:    client,
+		bridgeABI: bridgeABI,
+		stop:      stop,
+		sysErr:    sysErr,
+		expected:  make(map[proposalKey]*expectation),
+		progress:  make(map[utils.ChainId]*progress),
+		sightings: make(map[proposalKey][]*sighting),
+	}, nil
+}
+
+// Expect records data hash observer would have voted for and compares it with proposals already seen on-chain
+func (o *Observer) Expect(m *utils.Message, dataHash ethcommon.Hash) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	key := proposalKey{source: m.Source, nonce: m.DepositNonce}
+	o.expected[key] = &expectation{dataHash: dataHash, at: time.Now()}
+	p, ok := o.progress[m.Source]
+	if !ok {
+		o.progress[m.Source] = &progress{first: m.DepositNonce, last: m.DepositNonce}
+	} else if m.DepositNonce > p.last {
+		p.last = m.DepositNonce
+	}
+	log.Info().Interface("src", m.Source).Interface("dst", m.Destination).Interface("nonce", m.DepositNonce).Str("rId", m.ResourceId.Hex()).Str("dataHash", dataHash.Hex()).Msg("Observer would vote for proposal")
+	o.verify(key)
+}
+
+// Start polls destination chain for proposal events and votes starting from startBlock
+func (o *Observer) Start(startBlock *big.Int) {
+	currentBlock := new(big.Int).Set(startBlock)
+	go func() {
+		err := o.pollBlocks(currentBlock)
+		if err != nil {
+			log.Error().Err(err).Msg("Observer polling blocks failed")
+		}
+	}()
+}
+
+func (o *Observer) pollBlocks(currentBlock *big.Int) error {
+	log.Info().Interface("chain", o.chainID).Str("block", currentBlock.String()).Msg("Observing proposals...")
+	var retry = BlockRetryLimit
+	for {
+		select {
+		case <-o.stop:
+			return errors.New("polling terminated")
+		default:
+			if retry == 0 {
+				log.Error().Msg("Observer polling failed, retries exceeded")
+				o.sysErr <- ErrFatalPolling
+				return nil
+			}
+			latestBlock, err := o.client.LatestBlock()
+			if err != nil {
+				log.Error().Err(err).Str("block", currentBlock.String()).Msg("Unable to get latest block")
+				retry--
+				time.Sleep(BlockRetryInterval)
+				continue
+			}
+			if big.NewInt(0).Sub(latestBlock, currentBlock).Cmp(BlockDelay) == -1 {
+				time.Sleep(BlockRetryInterval)
+				continue
+			}
+			err = o.processBlock(currentBlock)
+			if err != nil {
+				log.Error().Str("block", currentBlock.String()).Err(err).Msg("Failed to observe proposals in block")
+				retry--
+				time.Sleep(BlockRetryInterval)
+				continue
+			}
+			currentBlock.Add(currentBlock, big.NewInt(1))
+			retry = BlockRetryLimit
+		}
+	}
+}
+
+func (o *Observer) processBlock(block *big.Int) error {
+	query := eth.FilterQuery{
+		FromBlock: block,
+		ToBlock:   block,
+		Addresses: []ethcommon.Address{o.bridge},
+		Topics: [][]ethcommon.Hash{
+			{utils.ProposalEvent.GetTopic(), utils.ProposalVote.GetTopic()},
+		},
+	}
+	logs, err := o.client.FilterLogs(context.Background(), query)
+	if err != nil {
+		return err
+	}
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	for _, evt := range logs {
+		if len(evt.Topics) != 4 {
+			continue
+		}
+		key := proposalKey{
+			source: utils.ChainId(evt.Topics[1].Big().Uint64()),
+			nonce:  utils.Nonce(evt.Topics[2].Big().Uint64()),
+		}
+		status := uint8(evt.Topics[3].Big().Uint64())
+		var dataHash ethcommon.Hash
+		if evt.Topics[0] == utils.ProposalEvent.GetTopic() {
+			if len(evt.Data) < 64 {
+				continue
+			}
+			dataHash = ethcommon.BytesToHash(evt.Data[32:64])
+		} else {
+			dataHash, err = o.voteDataHash(evt.TxHash)
+			if err != nil {
+				log.Debug().Err(err).Str("tx", evt.TxHash.Hex()).Msg("Unable to decode data hash of vote")
+				continue
+			}
+		}
+		o.sightings[key] = append(o.sightings[key], &sighting{dataHash: dataHash, block: new(big.Int).Set(block), tx: evt.TxHash})
+		o.verify(key)
+		if status == uint8(utils.Executed) || status == uint8(utils.Cancelled) {
+			delete(o.expected, key)
+		}
+	}
+	o.reportUnreproduced()
+	o.pruneExpected()
+	return nil
+}
+
+// voteDataHash decodes data hash from voteProposal transaction input
+func (o *Observer) voteDataHash(txHash ethcommon.Hash) (ethcommon.Hash, error) {
+	tx, _, err := o.client.TransactionByHash(context.Background(), txHash)
+	if err != nil {
+		return ethcommon.Hash{}, err
+	}
+	data := tx.Data()
+	method := o.bridgeABI.Methods[voteProposalMethod]
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID()) {
+		return ethcommon.Hash{}, errors.New("vote was not submitted by direct voteProposal call")
+	}
+	args, err := method.Inputs.UnpackValues(data[4:])
+	if err != nil {
+		return ethcommon.Hash{}, err
+	}
+	dataHash, ok := args[len(args)-1].([32]byte)
+	if !ok {
+		return ethcommon.Hash{}, errors.New("unexpected voteProposal arguments")
+	}
+	return dataHash, nil
+}
+
+// verify compares on-chain sightings of proposal with data hash computed by observer. Should be called under lock
+func (o *Observer) verify(key proposalKey) {
+	e, ok := o.expected[key]
+	if !ok {
+		return
+	}
+	expected := e.dataHash
+	for _, s := range o.sightings[key] {
+		if s.dataHash == expected {
+			log.Info().Interface("src", key.source).Interface("nonce", key.nonce).Str("dataHash", expected.Hex()).Str("tx", s.tx.Hex()).Msg("On-chain proposal matches observer")
+			continue
+		}
+		o.diverged++
+		log.Error().Bool("alert", true).Interface("chain", o.chainID).Interface("src", key.source).Interface("nonce", key.nonce).Str("expected", expected.Hex()).Str("onChain", s.dataHash.Hex()).Str("tx", s.tx.Hex()).Msg("On-chain proposal diverges from data hash computed by observer")
+	}
+	delete(o.sightings, key)
+}
+
+// reportUnreproduced reports on-chain proposals of deposits that source listener already processed but observer did
+// not compute data hash for. Proposals of deposits made before observer started are skipped. Should be called under lock
+func (o *Observer) reportUnreproduced() {
+	for key, sightings := range o.sightings {
+		p, ok := o.progress[key.source]
+		if !ok || key.nonce > p.last {
+			// Source listener did not reach the deposit yet
+			continue
+		}
+		delete(o.sightings, key)
+		if key.nonce < p.first {
+			log.Debug().Interface("src", key.source).Interface("nonce", key.nonce).Msg("Skipping on-chain proposal of deposit made before observer started")
+			continue
+		}
+		for _, s := range sightings {
+			o.diverged++
+			log.Error().Bool("alert", true).Interface("chain", o.chainID).Interface("src", key.source).Interface("nonce", key.nonce).Str("onChain", s.dataHash.Hex()).Str("tx", s.tx.Hex()).Msg("Observer can not reproduce on-chain proposal from source chain")
+		}
+	}
+}
+
+// pruneExpected forgets data hashes computed more than ExpectedTimeout ago. Should be called under lock
+func (o *Observer) pruneExpected() {
+	for key, e := range o.expected {
+		if time.Since(e.at) > ExpectedTimeout {
+			log.Debug().Interface("src", key.source).Interface("nonce", key.nonce).Msg("Forgetting proposal that was not finalized in time")
+			delete(o.expected, key)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package observer
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	mock_observer "github.com/ChainSafe/chainbridge-celo/observer/mock"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ObserverTestSuite struct {
+	suite.Suite
+	client           *mock_observer.MockChainReader
+	gomockController *gomock.Controller
+	observer         *Observer
+}
+
+func TestRunObserverTestSuite(t *testing.T) {
+	suite.Run(t, new(ObserverTestSuite))
+}
+
+func (s *ObserverTestSuite) SetupSuite()    {}
+func (s *ObserverTestSuite) TearDownSuite() {}
+func (s *ObserverTestSuite) SetupTest() {
+	s.gomockController = gomock.NewController(s.T())
+	s.client = mock_observer.NewMockChainReader(s.gomockController)
+	o, err := NewObserver(2, ethcommon.Address{}, s.client, make(chan struct{}), make(chan error))
+	s.Nil(err)
+	s.observer = o
+}
+func (s *ObserverTestSuite) TearDownTest() {}
+
+func proposalEventLog(source uint8, nonce uint64, status utils.ProposalStatus, dataHash ethcommon.Hash) types.Log {
+	data := make([]byte, 64)
+	copy(data[32:], dataHash.Bytes())
+	return types.Log{
+		Topics: []ethcommon.Hash{
+			utils.ProposalEvent.GetTopic(),
+			ethcommon.BigToHash(big.NewInt(int64(source))),
+			ethcommon.BigToHash(new(big.Int).SetUint64(nonce)),
+			ethcommon.BigToHash(big.NewInt(int64(status))),
+		},
+		Data:   data,
+		TxHash: ethcommon.Hash{byte(nonce)},
+	}
+}
+
+func (s *ObserverTestSuite) TestMatchingProposal() {
+	dataHash := ethcommon.Hash{1}
+	s.observer.Expect(utils.NewGenericTransfer(1, 2, 5, utils.ResourceId{}, nil, nil, []byte{}), dataHash)
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{proposalEventLog(1, 5, utils.Active, dataHash)}, nil)
+	s.Nil(s.observer.processBlock(big.NewInt(10)))
+	s.Equal(0, s.observer.diverged)
+	s.Equal(0, len(s.observer.sightings))
+}
+
+func (s *ObserverTestSuite) TestDivergingProposal() {
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{proposalEventLog(1, 5, utils.Active, ethcommon.Hash{2})}, nil)
+	s.Nil(s.observer.processBlock(big.NewInt(10)))
+	// Proposal is seen before observer processed the deposit, it is compared once expectation arrives
+	s.Equal(0, s.observer.diverged)
+	s.observer.Expect(utils.NewGenericTransfer(1, 2, 5, utils.ResourceId{}, nil, nil, []byte{}), ethcommon.Hash{1})
+	s.Equal(1, s.observer.diverged)
+}
+
+func (s *ObserverTestSuite) TestUnreproducedProposal() {
+	s.observer.Expect(utils.NewGenericTransfer(1, 2, 4, utils.ResourceId{}, nil, nil, []byte{}), ethcommon.Hash{1})
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{proposalEventLog(1, 5, utils.Active, ethcommon.Hash{2})}, nil)
+	s.Nil(s.observer.processBlock(big.NewInt(10)))
+	// Source listener did not reach the deposit yet, however far destination chain is
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{}, nil)
+	s.Nil(s.observer.processBlock(big.NewInt(1000)))
+	s.Equal(0, s.observer.diverged)
+	s.Equal(1, len(s.observer.sightings))
+
+	// Source listener went past the deposit without computing it
+	s.observer.Expect(utils.NewGenericTransfer(1, 2, 6, utils.ResourceId{}, nil, nil, []byte{}), ethcommon.Hash{3})
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{}, nil)
+	s.Nil(s.observer.processBlock(big.NewInt(1001)))
+	s.Equal(1, s.observer.diverged)
+	s.Equal(0, len(s.observer.sightings))
+}
+
+func (s *ObserverTestSuite) TestProposalBeforeObserverStartSkipped() {
+	s.observer.Expect(utils.NewGenericTransfer(1, 2, 5, utils.ResourceId{}, nil, nil, []byte{}), ethcommon.Hash{1})
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{proposalEventLog(1, 3, utils.Active, ethcommon.Hash{2})}, nil)
+	s.Nil(s.observer.processBlock(big.NewInt(10)))
+	s.Equal(0, s.observer.diverged)
+	s.Equal(0, len(s.observer.sightings))
+}
+
+func (s *ObserverTestSuite) TestExpiredExpectationPruned() {
+	s.observer.Expect(utils.NewGenericTransfer(1, 2, 5, utils.ResourceId{}, nil, nil, []byte{}), ethcommon.Hash{1})
+	s.observer.Expect(utils.NewGenericTransfer(1, 2, 6, utils.ResourceId{}, nil, nil, []byte{}), ethcommon.Hash{2})
+	s.observer.expected[proposalKey{source: 1, nonce: 5}].at = time.Now().Add(-ExpectedTimeout - time.Minute)
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{}, nil)
+	s.Nil(s.observer.processBlock(big.NewInt(10)))
+	s.Equal(1, len(s.observer.expected))
+	s.NotNil(s.observer.expected[proposalKey{source: 1, nonce: 6}])
+}
+
+func (s *ObserverTestSuite) TestVoteDataHashDecoded() {
+	dataHash := ethcommon.Hash{3}
+	input, err := s.observer.bridgeABI.Pack(voteProposalMethod, uint8(1), uint64(5), [32]byte{}, [32]byte(dataHash))
+	s.Nil(err)
+	tx := types.NewTransaction(0, ethcommon.Address{}, big.NewInt(0), 0, big.NewInt(0), nil, nil, nil, input)
+	vote := proposalEventLog(1, 5, utils.Active, ethcommon.Hash{})
+	vote.Topics[0] = utils.ProposalVote.GetTopic()
+	vote.Data = make([]byte, 32)
+
+	s.observer.Expect(utils.NewGenericTransfer(1, 2, 5, utils.ResourceId{}, nil, nil, []byte{}), ethcommon.Hash{4})
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{vote}, nil)
+	s.client.EXPECT().TransactionByHash(gomock.Any(), vote.TxHash).Return(tx, false, nil)
+	s.Nil(s.observer.processBlock(big.NewInt(10)))
+	s.Equal(1, s.observer.diverged)
+}
+
+func (s *ObserverTestSuite) TestFinalizedProposalForgotten() {
+	dataHash := ethcommon.Hash{1}
+	s.observer.Expect(utils.NewGenericTransfer(1, 2, 5, utils.ResourceId{}, nil, nil, []byte{}), dataHash)
+	s.client.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{proposalEventLog(1, 5, utils.Executed, dataHash)}, nil)
+	s.Nil(s.observer.processBlock(big.NewInt(10)))
+	s.Equal(0, len(s.observer.expected))
+}
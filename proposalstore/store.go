@@ -0,0 +1,147 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package proposalstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	votedKeyPrefix  = "proposalVoted"
+	passedKeyPrefix = "proposalPassed"
+	sweptKeyPrefix  = "proposalSweptBlock"
+)
+
+// VotedProposal is a proposal relayer voted on that is not passed yet
+type VotedProposal struct {
+	Message  *utils.Message `json:"message"`
+	DataHash common.Hash    `json:"dataHash"`
+}
+
+// PassedProposal is a proposal that passed on destination chain and was not executed yet
+type PassedProposal struct {
+	Source   utils.ChainId `json:"source"`
+	Nonce    utils.Nonce   `json:"depositNonce"`
+	DataHash common.Hash   `json:"dataHash"`
+	Block    uint64        `json:"block"` // Block proposal passed in
+}
+
+// Store persists proposals writer of a single destination chain keeps track of and the last block scanned for passed
+// proposals, so they survive relayer restarts
+type Store struct {
+	db      *leveldb.DB
+	chainID utils.ChainId
+}
+
+func NewStore(db *leveldb.DB, chainID utils.ChainId) *Store {
+	return &Store{
+		db:      db,
+		chainID: chainID,
+	}
+}
+
+// PutVoted persists voted proposal replacing previous one of the same deposit
+func (s *Store) PutVoted(p *VotedProposal) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(proposalKey(votedKeyPrefix, s.chainID, p.Message.Source, p.Message.DepositNonce), data, nil)
+}
+
+// DeleteVoted removes voted proposal of deposit from source with nonce
+func (s *Store) DeleteVoted(source utils.ChainId, nonce utils.Nonce) error {
+	return s.db.Delete(proposalKey(votedKeyPrefix, s.chainID, source, nonce), nil)
+}
+
+// Voted returns all persisted voted proposals
+func (s *Store) Voted() ([]*VotedProposal, error) {
+	res := make([]*VotedProposal, 0)
+	iter := s.db.NewIterator(util.BytesPrefix(chainPrefix(votedKeyPrefix, s.chainID)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		p := &VotedProposal{}
+		err := json.Unmarshal(iter.Value(), p)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// PutPassed persists passed proposal replacing previous one of the same deposit
+func (s *Store) PutPassed(p *PassedProposal) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(proposalKey(passedKeyPrefix, s.chainID, p.Source, p.Nonce), data, nil)
+}
+
+// DeletePassed removes passed proposal of deposit from source with nonce
+func (s *Store) DeletePassed(source utils.ChainId, nonce utils.Nonce) error {
+	return s.db.Delete(proposalKey(passedKeyPrefix, s.chainID, source, nonce), nil)
+}
+
+// Passed returns all persisted passed proposals
+func (s *Store) Passed() ([]*PassedProposal, error) {
+	res := make([]*PassedProposal, 0)
+	iter := s.db.NewIterator(util.BytesPrefix(chainPrefix(passedKeyPrefix, s.chainID)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		p := &PassedProposal{}
+		err := json.Unmarshal(iter.Value(), p)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SweptBlock returns the last block scanned for passed proposals, nil is returned if chain was never scanned
+func (s *Store) SweptBlock() (*big.Int, error) {
+	data, err := s.db.Get(chainPrefix(sweptKeyPrefix, s.chainID), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// SetSweptBlock persists the last block scanned for passed proposals
+func (s *Store) SetSweptBlock(block *big.Int) error {
+	return s.db.Put(chainPrefix(sweptKeyPrefix, s.chainID), block.Bytes(), nil)
+}
+
+func chainPrefix(prefix string, chainID utils.ChainId) []byte {
+	key := bytes.NewBufferString(prefix)
+	key.WriteByte(uint8(chainID))
+	return key.Bytes()
+}
+
+func proposalKey(prefix string, chainID utils.ChainId, source utils.ChainId, nonce utils.Nonce) []byte {
+	key := bytes.NewBuffer(chainPrefix(prefix, chainID))
+	key.WriteByte(uint8(source))
+	_ = binary.Write(key, binary.BigEndian, uint64(nonce))
+	return key.Bytes()
+}
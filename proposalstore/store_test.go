@@ -0,0 +1,89 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package proposalstore
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/suite"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+type StoreTestSuite struct {
+	suite.Suite
+	db    *leveldb.DB
+	store *Store
+}
+
+func TestRunStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(StoreTestSuite))
+}
+
+func (s *StoreTestSuite) SetupSuite()    {}
+func (s *StoreTestSuite) TearDownSuite() {}
+func (s *StoreTestSuite) SetupTest() {
+	db, err := leveldb.OpenFile("./test/db", nil)
+	if err != nil {
+		s.Fail(err.Error())
+	}
+	s.db = db
+	s.store = NewStore(db, 2)
+}
+func (s *StoreTestSuite) TearDownTest() {
+	s.db.Close()
+	os.RemoveAll("./test")
+}
+
+func (s *StoreTestSuite) TestVoted() {
+	m := utils.NewFungibleTransfer(1, 2, 5, utils.ResourceId{1}, &utils.MerkleProof{}, &utils.SignatureVerification{}, big.NewInt(10), []byte{1})
+	s.Nil(s.store.PutVoted(&VotedProposal{Message: m, DataHash: common.Hash{1}}))
+	s.Nil(s.store.PutVoted(&VotedProposal{Message: utils.NewGenericTransfer(1, 2, 6, utils.ResourceId{2}, nil, nil, []byte{}), DataHash: common.Hash{2}}))
+	// Proposals of other destination chains are not listed
+	s.Nil(NewStore(s.db, 3).PutVoted(&VotedProposal{Message: m, DataHash: common.Hash{3}}))
+
+	voted, err := s.store.Voted()
+	s.Nil(err)
+	s.Len(voted, 2)
+	s.Equal(utils.Nonce(5), voted[0].Message.DepositNonce)
+	s.Equal(common.Hash{1}, voted[0].DataHash)
+
+	s.Nil(s.store.DeleteVoted(1, 5))
+	voted, err = s.store.Voted()
+	s.Nil(err)
+	s.Len(voted, 1)
+	s.Equal(utils.Nonce(6), voted[0].Message.DepositNonce)
+}
+
+func (s *StoreTestSuite) TestPassed() {
+	s.Nil(s.store.PutPassed(&PassedProposal{Source: 1, Nonce: 5, DataHash: common.Hash{1}, Block: 10}))
+	s.Nil(s.store.PutPassed(&PassedProposal{Source: 3, Nonce: 1, DataHash: common.Hash{2}, Block: 11}))
+	passed, err := s.store.Passed()
+	s.Nil(err)
+	s.Len(passed, 2)
+	s.Equal(&PassedProposal{Source: 1, Nonce: 5, DataHash: common.Hash{1}, Block: 10}, passed[0])
+
+	s.Nil(s.store.DeletePassed(1, 5))
+	passed, err = s.store.Passed()
+	s.Nil(err)
+	s.Len(passed, 1)
+	s.Equal(utils.ChainId(3), passed[0].Source)
+}
+
+func (s *StoreTestSuite) TestSweptBlock() {
+	block, err := s.store.SweptBlock()
+	s.Nil(err)
+	s.Nil(block)
+	s.Nil(s.store.SetSweptBlock(big.NewInt(1000)))
+	block, err = s.store.SweptBlock()
+	s.Nil(err)
+	s.Equal(big.NewInt(1000), block)
+	// Swept block of other chain is not affected
+	block, err = NewStore(s.db, 3).SweptBlock()
+	s.Nil(err)
+	s.Nil(block)
+}
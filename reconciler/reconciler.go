@@ -0,0 +1,229 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package reconciler
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/bindings/Bridge"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+)
+
+// Number of blocks ProposalEvent logs are queried for at once
+var SearchRange = big.NewInt(10000)
+
+// Number of blocks after which Active or Passed proposal is reported as stuck
+var StuckBlocks = big.NewInt(100)
+
+type ChainReader interface {
+	FilterLogs(ctx context.Context, q eth.FilterQuery) ([]types.Log, error)
+	LatestBlock() (*big.Int, error)
+}
+
+type BridgeReader interface {
+	DepositCounts(opts *bind.CallOpts, arg0 uint8) (uint64, error)
+	GetProposal(opts *bind.CallOpts, originChainID uint8, depositNonce uint64, dataHash [32]byte) (Bridge.BridgeProposal, error)
+}
+
+// Chain is bridge deployment reconciled with other chains
+type Chain struct {
+	ID         utils.ChainId
+	Bridge     ethcommon.Address
+	Client     ChainReader
+	Contract   BridgeReader
+	StartBlock *big.Int // Block ProposalEvent logs are scanned from, eg. bridge deployment block
+}
+
+type proposalKey struct {
+	source utils.ChainId
+	nonce  utils.Nonce
+}
+
+// proposalRecord is the latest ProposalEvent seen for deposit
+type proposalRecord struct {
+	dataHash ethcommon.Hash
+	final    *Bridge.BridgeProposal // Executed or Cancelled proposal, its status is not read from the bridge again
+}
+
+// Reconciler compares deposit nonces of every chain pair with proposals on destination chain.
+// ProposalEvent logs are scanned incrementally, so consecutive reconciliations only query new blocks
+type Reconciler struct {
+	chains    []*Chain
+	proposals map[utils.ChainId]map[proposalKey]*proposalRecord // proposals seen per destination chain
+	scanned   map[utils.ChainId]*big.Int                        // last scanned block per destination chain
+}
+
+func NewReconciler(chains []*Chain) *Reconciler {
+	return &Reconciler{
+		chains:    chains,
+		proposals: make(map[utils.ChainId]map[proposalKey]*proposalRecord),
+		scanned:   make(map[utils.ChainId]*big.Int),
+	}
+}
+
+// Reconcile scans new proposal events of every chain and reports deposits that were never proposed,
+// proposals stuck in Active or Passed status and cancelled proposals
+func (r *Reconciler) Reconcile() (*Report, error) {
+	report := &Report{GeneratedAt: time.Now().UTC(), Pairs: make([]*PairSummary, 0), Entries: make([]*Entry, 0)}
+	latest := make(map[utils.ChainId]*big.Int)
+	for _, dest := range r.chains {
+		latestBlock, err := dest.Client.LatestBlock()
+		if err != nil {
+			return nil, err
+		}
+		err = r.scanProposals(dest, latestBlock)
+		if err != nil {
+			return nil, err
+		}
+		latest[dest.ID] = latestBlock
+	}
+	for _, src := range r.chains {
+		for _, dest := range r.chains {
+			if src.ID == dest.ID {
+				continue
+			}
+			entries, summary, err := r.reconcilePair(src, dest, latest[dest.ID])
+			if err != nil {
+				return nil, err
+			}
+			report.Pairs = append(report.Pairs, summary)
+			report.Entries = append(report.Entries, entries...)
+		}
+	}
+	return report, nil
+}
+
+// scanProposals records ProposalEvent logs of dest chain up to latestBlock
+func (r *Reconciler) scanProposals(dest *Chain, latestBlock *big.Int) error {
+	from := new(big.Int).Set(dest.StartBlock)
+	if scanned, ok := r.scanned[dest.ID]; ok {
+		from = new(big.Int).Add(scanned, big.NewInt(1))
+	}
+	proposals, ok := r.proposals[dest.ID]
+	if !ok {
+		proposals = make(map[proposalKey]*proposalRecord)
+		r.proposals[dest.ID] = proposals
+	}
+	for from.Cmp(latestBlock) <= 0 {
+		to := new(big.Int).Add(from, SearchRange)
+		to.Sub(to, big.NewInt(1))
+		if to.Cmp(latestBlock) > 0 {
+			to.Set(latestBlock)
+		}
+		query := eth.FilterQuery{
+			FromBlock: from,
+			ToBlock:   to,
+			Addresses: []ethcommon.Address{dest.Bridge},
+			Topics:    [][]ethcommon.Hash{{utils.ProposalEvent.GetTopic()}},
+		}
+		logs, err := dest.Client.FilterLogs(context.Background(), query)
+		if err != nil {
+			return err
+		}
+		for _, evt := range logs {
+			if len(evt.Topics) != 4 || len(evt.Data) < 64 {
+				continue
+			}
+			key := proposalKey{
+				source: utils.ChainId(evt.Topics[1].Big().Uint64()),
+				nonce:  utils.Nonce(evt.Topics[2].Big().Uint64()),
+			}
+			proposals[key] = &proposalRecord{
+				dataHash: ethcommon.BytesToHash(evt.Data[32:64]),
+			}
+		}
+		r.scanned[dest.ID] = new(big.Int).Set(to)
+		from = to.Add(to, big.NewInt(1))
+	}
+	return nil
+}
+
+// reconcilePair compares deposits made on src to dest with proposals on dest
+func (r *Reconciler) reconcilePair(src, dest *Chain, latestBlock *big.Int) ([]*Entry, *PairSummary, error) {
+	count, err := src.Contract.DepositCounts(&bind.CallOpts{}, uint8(dest.ID))
+	if err != nil {
+		return nil, nil, err
+	}
+	summary := &PairSummary{Source: src.ID, Destination: dest.ID, Deposits: count}
+	entries := make([]*Entry, 0)
+	for nonce := uint64(1); nonce <= count; nonce++ {
+		record, ok := r.proposals[dest.ID][proposalKey{source: src.ID, nonce: utils.Nonce(nonce)}]
+		if !ok {
+			entries = append(entries, &Entry{Source: src.ID, Destination: dest.ID, Nonce: utils.Nonce(nonce), Issue: IssueNotProposed})
+			continue
+		}
+		// Proposal status is read from the bridge, events only provide data hash. Final statuses can not change,
+		// so proposal is read once it is executed or cancelled
+		prop, err := r.proposalStatus(src, dest, nonce, record)
+		if err != nil {
+			return nil, nil, err
+		}
+		entry := &Entry{
+			Source:        src.ID,
+			Destination:   dest.ID,
+			Nonce:         utils.Nonce(nonce),
+			Status:        StatusName(prop.Status),
+			DataHash:      record.dataHash.Hex(),
+			ProposedBlock: prop.ProposedBlock,
+		}
+		switch utils.ProposalStatus(prop.Status) {
+		case utils.Active, utils.Passed:
+			if prop.ProposedBlock == nil || new(big.Int).Sub(latestBlock, prop.ProposedBlock).Cmp(StuckBlocks) <= 0 {
+				continue
+			}
+			entry.Issue = IssueStuck
+		case utils.Cancelled:
+			entry.Issue = IssueCancelled
+		default:
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	summary.Issues = len(entries)
+	return entries, summary, nil
+}
+
+// proposalStatus returns proposal of record from dest bridge unless it is already known to be final
+func (r *Reconciler) proposalStatus(src, dest *Chain, nonce uint64, record *proposalRecord) (Bridge.BridgeProposal, error) {
+	if record.final != nil {
+		return *record.final, nil
+	}
+	prop, err := dest.Contract.GetProposal(&bind.CallOpts{}, uint8(src.ID), nonce, record.dataHash)
+	if err != nil {
+		return Bridge.BridgeProposal{}, err
+	}
+	switch utils.ProposalStatus(prop.Status) {
+	case utils.Executed, utils.Cancelled:
+		record.final = &prop
+	}
+	return prop, nil
+}
+
+// Start reconciles chains every interval and logs found issues until stop is closed
+func (r *Reconciler) Start(stop <-chan struct{}, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				report, err := r.Reconcile()
+				if err != nil {
+					log.Error().Err(err).Msg("Failed to reconcile deposit nonces")
+					continue
+				}
+				report.Log()
+			}
+		}
+	}()
+}
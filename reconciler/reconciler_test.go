@@ -0,0 +1,174 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package reconciler
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-celo/bindings/Bridge"
+	mock_reconciler "github.com/ChainSafe/chainbridge-celo/reconciler/mock"
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	eth "github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+type ReconcilerTestSuite struct {
+	suite.Suite
+	gomockController *gomock.Controller
+	client1          *mock_reconciler.MockChainReader
+	client2          *mock_reconciler.MockChainReader
+	bridge1          *mock_reconciler.MockBridgeReader
+	bridge2          *mock_reconciler.MockBridgeReader
+	reconciler       *Reconciler
+}
+
+func TestRunReconcilerTestSuite(t *testing.T) {
+	suite.Run(t, new(ReconcilerTestSuite))
+}
+
+func (s *ReconcilerTestSuite) SetupSuite()    {}
+func (s *ReconcilerTestSuite) TearDownSuite() {}
+func (s *ReconcilerTestSuite) SetupTest() {
+	s.gomockController = gomock.NewController(s.T())
+	s.client1 = mock_reconciler.NewMockChainReader(s.gomockController)
+	s.client2 = mock_reconciler.NewMockChainReader(s.gomockController)
+	s.bridge1 = mock_reconciler.NewMockBridgeReader(s.gomockController)
+	s.bridge2 = mock_reconciler.NewMockBridgeReader(s.gomockController)
+	s.reconciler = NewReconciler([]*Chain{
+		{ID: 1, Bridge: ethcommon.Address{1}, Client: s.client1, Contract: s.bridge1, StartBlock: big.NewInt(0)},
+		{ID: 2, Bridge: ethcommon.Address{2}, Client: s.client2, Contract: s.bridge2, StartBlock: big.NewInt(100)},
+	})
+}
+func (s *ReconcilerTestSuite) TearDownTest() {}
+
+func proposalEventLog(source uint8, nonce uint64, status utils.ProposalStatus, dataHash ethcommon.Hash) types.Log {
+	data := make([]byte, 64)
+	copy(data[32:], dataHash.Bytes())
+	return types.Log{
+		Topics: []ethcommon.Hash{
+			utils.ProposalEvent.GetTopic(),
+			ethcommon.BigToHash(big.NewInt(int64(source))),
+			ethcommon.BigToHash(new(big.Int).SetUint64(nonce)),
+			ethcommon.BigToHash(big.NewInt(int64(status))),
+		},
+		Data: data,
+	}
+}
+
+func (s *ReconcilerTestSuite) TestReconcile() {
+	s.client1.EXPECT().LatestBlock().Return(big.NewInt(50), nil)
+	s.client1.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{}, nil)
+	s.client2.EXPECT().LatestBlock().Return(big.NewInt(1000), nil)
+	s.client2.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, q eth.FilterQuery) ([]types.Log, error) {
+		s.Equal(big.NewInt(100), q.FromBlock)
+		s.Equal(big.NewInt(1000), q.ToBlock)
+		s.Equal([]ethcommon.Address{{2}}, q.Addresses)
+		return []types.Log{
+			proposalEventLog(1, 1, utils.Active, ethcommon.Hash{1}),
+			proposalEventLog(1, 1, utils.Executed, ethcommon.Hash{1}),
+			proposalEventLog(1, 2, utils.Active, ethcommon.Hash{2}),
+			proposalEventLog(1, 3, utils.Cancelled, ethcommon.Hash{3}),
+			proposalEventLog(1, 5, utils.Passed, ethcommon.Hash{5}),
+		}, nil
+	})
+	s.bridge1.EXPECT().DepositCounts(gomock.Any(), uint8(2)).Return(uint64(5), nil)
+	s.bridge2.EXPECT().DepositCounts(gomock.Any(), uint8(1)).Return(uint64(0), nil)
+	s.bridge2.EXPECT().GetProposal(gomock.Any(), uint8(1), uint64(1), [32]byte{1}).Return(Bridge.BridgeProposal{Status: uint8(utils.Executed), ProposedBlock: big.NewInt(200)}, nil)
+	s.bridge2.EXPECT().GetProposal(gomock.Any(), uint8(1), uint64(2), [32]byte{2}).Return(Bridge.BridgeProposal{Status: uint8(utils.Active), ProposedBlock: big.NewInt(300)}, nil)
+	s.bridge2.EXPECT().GetProposal(gomock.Any(), uint8(1), uint64(3), [32]byte{3}).Return(Bridge.BridgeProposal{Status: uint8(utils.Cancelled), ProposedBlock: big.NewInt(400)}, nil)
+	// Recently passed proposal is not stuck yet
+	s.bridge2.EXPECT().GetProposal(gomock.Any(), uint8(1), uint64(5), [32]byte{5}).Return(Bridge.BridgeProposal{Status: uint8(utils.Passed), ProposedBlock: big.NewInt(990)}, nil)
+
+	report, err := s.reconciler.Reconcile()
+	s.Nil(err)
+	s.Len(report.Pairs, 2)
+	s.Equal(uint64(5), report.Pairs[0].Deposits)
+	s.Equal(3, report.Pairs[0].Issues)
+	s.Len(report.Entries, 3)
+	s.Equal(utils.Nonce(2), report.Entries[0].Nonce)
+	s.Equal(IssueStuck, report.Entries[0].Issue)
+	s.Equal("Active", report.Entries[0].Status)
+	s.Equal(utils.Nonce(3), report.Entries[1].Nonce)
+	s.Equal(IssueCancelled, report.Entries[1].Issue)
+	s.Equal(utils.Nonce(4), report.Entries[2].Nonce)
+	s.Equal(IssueNotProposed, report.Entries[2].Issue)
+}
+
+func (s *ReconcilerTestSuite) TestReconcileScansIncrementally() {
+	s.reconciler = NewReconciler([]*Chain{
+		{ID: 1, Client: s.client1, Contract: s.bridge1, StartBlock: big.NewInt(0)},
+	})
+	s.client1.EXPECT().LatestBlock().Return(big.NewInt(15000), nil)
+	s.client1.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, q eth.FilterQuery) ([]types.Log, error) {
+		s.Equal(big.NewInt(0), q.FromBlock)
+		s.Equal(big.NewInt(9999), q.ToBlock)
+		return []types.Log{}, nil
+	})
+	s.client1.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, q eth.FilterQuery) ([]types.Log, error) {
+		s.Equal(big.NewInt(10000), q.FromBlock)
+		s.Equal(big.NewInt(15000), q.ToBlock)
+		return []types.Log{}, nil
+	})
+	_, err := s.reconciler.Reconcile()
+	s.Nil(err)
+
+	s.client1.EXPECT().LatestBlock().Return(big.NewInt(15010), nil)
+	s.client1.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, q eth.FilterQuery) ([]types.Log, error) {
+		s.Equal(big.NewInt(15001), q.FromBlock)
+		s.Equal(big.NewInt(15010), q.ToBlock)
+		return []types.Log{}, nil
+	})
+	_, err = s.reconciler.Reconcile()
+	s.Nil(err)
+}
+
+func (s *ReconcilerTestSuite) TestReconcileSkipsFinalProposals() {
+	s.reconciler = NewReconciler([]*Chain{
+		{ID: 1, Client: s.client1, Contract: s.bridge1, StartBlock: big.NewInt(0)},
+		{ID: 2, Client: s.client2, Contract: s.bridge2, StartBlock: big.NewInt(0)},
+	})
+	s.client1.EXPECT().LatestBlock().Return(big.NewInt(1000), nil).Times(2)
+	s.client1.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{}, nil)
+	s.client2.EXPECT().LatestBlock().Return(big.NewInt(1000), nil).Times(2)
+	s.client2.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{
+		proposalEventLog(1, 1, utils.Executed, ethcommon.Hash{1}),
+		proposalEventLog(1, 2, utils.Cancelled, ethcommon.Hash{2}),
+		proposalEventLog(1, 3, utils.Active, ethcommon.Hash{3}),
+	}, nil)
+	s.bridge1.EXPECT().DepositCounts(gomock.Any(), uint8(2)).Return(uint64(3), nil).Times(2)
+	s.bridge2.EXPECT().DepositCounts(gomock.Any(), uint8(1)).Return(uint64(0), nil).Times(2)
+	// Executed and cancelled proposals are read once, active one on every reconciliation
+	s.bridge2.EXPECT().GetProposal(gomock.Any(), uint8(1), uint64(1), [32]byte{1}).Return(Bridge.BridgeProposal{Status: uint8(utils.Executed), ProposedBlock: big.NewInt(200)}, nil)
+	s.bridge2.EXPECT().GetProposal(gomock.Any(), uint8(1), uint64(2), [32]byte{2}).Return(Bridge.BridgeProposal{Status: uint8(utils.Cancelled), ProposedBlock: big.NewInt(300)}, nil)
+	s.bridge2.EXPECT().GetProposal(gomock.Any(), uint8(1), uint64(3), [32]byte{3}).Return(Bridge.BridgeProposal{Status: uint8(utils.Active), ProposedBlock: big.NewInt(990)}, nil).Times(2)
+
+	_, err := s.reconciler.Reconcile()
+	s.Nil(err)
+	report, err := s.reconciler.Reconcile()
+	s.Nil(err)
+	s.Len(report.Entries, 1)
+	s.Equal(utils.Nonce(2), report.Entries[0].Nonce)
+	s.Equal(IssueCancelled, report.Entries[0].Issue)
+}
+
+func (s *ReconcilerTestSuite) TestWriteCSV() {
+	report := &Report{Entries: []*Entry{
+		{Source: 1, Destination: 2, Nonce: 2, Issue: IssueStuck, Status: "Passed", DataHash: "0x02", ProposedBlock: big.NewInt(300)},
+		{Source: 1, Destination: 2, Nonce: 4, Issue: IssueNotProposed},
+	}}
+	buf := new(bytes.Buffer)
+	s.Nil(report.WriteCSV(buf))
+	s.Equal([]string{
+		"source,destination,nonce,issue,status,dataHash,proposedBlock",
+		"1,2,2,stuck,Passed,0x02,300",
+		"1,2,4,not_proposed,,,",
+	}, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package reconciler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/rs/zerolog/log"
+)
+
+type Issue string
+
+const (
+	IssueNotProposed Issue = "not_proposed" // Deposit has no proposal on destination chain
+	IssueStuck       Issue = "stuck"        // Proposal stays Active or Passed longer than StuckBlocks
+	IssueCancelled   Issue = "cancelled"    // Proposal was cancelled
+)
+
+var statusNames = map[utils.ProposalStatus]string{
+	utils.Inactive:  "Inactive",
+	utils.Active:    "Active",
+	utils.Passed:    "Passed",
+	utils.Executed:  "Executed",
+	utils.Cancelled: "Cancelled",
+}
+
+// StatusName returns name of bridge proposal status
+func StatusName(status uint8) string {
+	if name, ok := statusNames[utils.ProposalStatus(status)]; ok {
+		return name
+	}
+	return fmt.Sprintf("Unknown(%d)", status)
+}
+
+// Entry is deposit which proposal needs operator attention
+type Entry struct {
+	Source        utils.ChainId `json:"source"`
+	Destination   utils.ChainId `json:"destination"`
+	Nonce         utils.Nonce   `json:"nonce"`
+	Issue         Issue         `json:"issue"`
+	Status        string        `json:"status,omitempty"`
+	DataHash      string        `json:"dataHash,omitempty"`
+	ProposedBlock *big.Int      `json:"proposedBlock,omitempty"`
+}
+
+// PairSummary is number of deposits and issues found for chain pair
+type PairSummary struct {
+	Source      utils.ChainId `json:"source"`
+	Destination utils.ChainId `json:"destination"`
+	Deposits    uint64        `json:"deposits"`
+	Issues      int           `json:"issues"`
+}
+
+type Report struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Pairs       []*PairSummary `json:"pairs"`
+	Entries     []*Entry       `json:"entries"`
+}
+
+// WriteJSON writes indented JSON report
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes report entries as CSV with header row
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"source", "destination", "nonce", "issue", "status", "dataHash", "proposedBlock"})
+	if err != nil {
+		return err
+	}
+	for _, e := range r.Entries {
+		proposedBlock := ""
+		if e.ProposedBlock != nil {
+			proposedBlock = e.ProposedBlock.String()
+		}
+		err = cw.Write([]string{
+			strconv.Itoa(int(e.Source)),
+			strconv.Itoa(int(e.Destination)),
+			strconv.FormatUint(uint64(e.Nonce), 10),
+			string(e.Issue),
+			e.Status,
+			e.DataHash,
+			proposedBlock,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Log logs summary of every chain pair and every found issue
+func (r *Report) Log() {
+	for _, p := range r.Pairs {
+		log.Info().Interface("src", p.Source).Interface("dst", p.Destination).Uint64("deposits", p.Deposits).Int("issues", p.Issues).Msg("Reconciled deposit nonces")
+	}
+	for _, e := range r.Entries {
+		log.Warn().Interface("src", e.Source).Interface("dst", e.Destination).Interface("nonce", e.Nonce).Str("issue", string(e.Issue)).Str("status", e.Status).Str("dataHash", e.DataHash).Msg("Deposit needs attention")
+	}
+}
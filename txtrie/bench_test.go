@@ -0,0 +1,77 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package txtrie
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const benchmarkBlockSize = 1000
+
+// Indexes of deposit transactions in benchmark block
+var benchmarkDeposits = []uint{0, 3, 127, 128, 400, 512, 777, 999}
+
+func BenchmarkCreateNewTrieAndRetrieveProofs(b *testing.B) {
+	txs := generateTransactions(benchmarkBlockSize)
+	root := types.DeriveSha(txs)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr, err := CreateNewTrie(root, txs)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, index := range benchmarkDeposits {
+			keyRlp, err := rlp.EncodeToBytes(index)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_, _, err = RetrieveProof(tr, keyRlp)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkNewTrieAndProveIndexes(b *testing.B) {
+	txs := generateTransactions(benchmarkBlockSize)
+	root := types.DeriveSha(txs)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr, err := NewTrie(root, txs)
+		if err != nil {
+			b.Fatal(err)
+		}
+		_, err = tr.ProveIndexes(benchmarkDeposits)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCachedTrieProofs measures retried block whose trie and proofs are already cached
+func BenchmarkCachedTrieProofs(b *testing.B) {
+	txs := generateTransactions(benchmarkBlockSize)
+	root := types.DeriveSha(txs)
+	c := NewCache(16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr, ok := c.Get(root)
+		if !ok {
+			var err error
+			tr, err = NewTrie(root, txs)
+			if err != nil {
+				b.Fatal(err)
+			}
+			c.Add(root, tr)
+		}
+		_, err := tr.ProveIndexes(benchmarkDeposits)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package txtrie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var ErrKeyNotFound = errors.New("key not found in trie")
+
+// Proof is merkle proof of single trie key in the format produced by RetrieveProof
+type Proof struct {
+	Key   []byte // Nibble path of the key
+	Nodes []byte // RLP list of nodes from root to leaf
+}
+
+// Trie is transactions trie of a block built in a single pass over sorted keys, stack trie style,
+// without backing database. Encoded nodes are kept in memory so proofs of many keys are retrieved without rebuilding it
+type Trie struct {
+	root   *node
+	hash   common.Hash
+	proofs map[uint]*Proof
+	lock   sync.Mutex
+}
+
+type node struct {
+	enc      []byte    // RLP encoding of node
+	path     []byte    // Nibbles of leaf or extension node, nil for branch node
+	leaf     bool      // Leaf node holds value at the end of path
+	child    *node     // Child of extension node
+	children [16]*node // Children of branch node
+}
+
+type entry struct {
+	key   []byte // Nibbles of RLP encoded index
+	index uint
+	value []byte
+}
+
+// NewTrie builds trie of list items keyed by RLP encoded index and verifies it against root
+func NewTrie(root common.Hash, list types.DerivableList) (*Trie, error) {
+	t, err := BuildTrie(list)
+	if err != nil {
+		return nil, err
+	}
+	if t.hash != root {
+		return nil, fmt.Errorf("trie roots don't match, expected %s got %s", root.Hex(), t.hash.Hex())
+	}
+	return t, nil
+}
+
+// BuildTrie builds trie of list items keyed by RLP encoded index
+func BuildTrie(list types.DerivableList) (*Trie, error) {
+	t := &Trie{hash: emptyRoot, proofs: make(map[uint]*Proof)}
+	if list.Len() == 0 {
+		return t, nil
+	}
+	entries := make([]*entry, list.Len())
+	for i := range entries {
+		key, err := KeyForIndex(uint(i))
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = &entry{key: key, index: uint(i), value: list.GetRlp(i)}
+	}
+	// RLP encoding of indexes is not ordered, eg. 0 is encoded as 0x80
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].key, entries[j].key) < 0 })
+	root, err := buildNode(entries, 0)
+	if err != nil {
+		return nil, err
+	}
+	t.root = root
+	t.hash = crypto.Keccak256Hash(root.enc)
+	return t, nil
+}
+
+// Hash returns root hash of trie
+func (t *Trie) Hash() common.Hash {
+	return t.hash
+}
+
+// Prove returns proof of item with index
+func (t *Trie) Prove(index uint) (*Proof, error) {
+	proofs, err := t.ProveIndexes([]uint{index})
+	if err != nil {
+		return nil, err
+	}
+	return proofs[index], nil
+}
+
+// ProveIndexes returns proofs of items with indexes. Proofs not retrieved before are collected in one walk
+// over the trie visiting nodes shared by their paths once
+func (t *Trie) ProveIndexes(indexes []uint) (map[uint]*Proof, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	res := make(map[uint]*Proof, len(indexes))
+	targets := make([]*entry, 0)
+	for _, index := range indexes {
+		if p, ok := t.proofs[index]; ok {
+			res[index] = p
+			continue
+		}
+		key, err := KeyForIndex(index)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, &entry{key: key, index: index})
+	}
+	if len(targets) == 0 {
+		return res, nil
+	}
+	if t.root == nil {
+		return nil, ErrKeyNotFound
+	}
+	err := collectProofs(t.root, 0, targets, [][]byte{t.root.enc}, t.proofs)
+	if err != nil {
+		return nil, err
+	}
+	for _, target := range targets {
+		res[target.index] = t.proofs[target.index]
+	}
+	return res, nil
+}
+
+// collectProofs descends to every target key from n at depth. Nodes that are referenced by hash are added to proofs,
+// embedded ones are part of their parent encoding
+func collectProofs(n *node, depth int, targets []*entry, path [][]byte, proofs map[uint]*Proof) error {
+	if n.path != nil {
+		rest := make([]*entry, 0, len(targets))
+		for _, target := range targets {
+			if len(target.key)-depth < len(n.path) || !bytes.Equal(target.key[depth:depth+len(n.path)], n.path) {
+				return fmt.Errorf("%w: index %d", ErrKeyNotFound, target.index)
+			}
+			if n.leaf {
+				if len(target.key) != depth+len(n.path) {
+					return fmt.Errorf("%w: index %d", ErrKeyNotFound, target.index)
+				}
+				err := addProof(target, path, proofs)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			rest = append(rest, target)
+		}
+		if n.leaf {
+			return nil
+		}
+		return collectProofs(n.child, depth+len(n.path), rest, appendNode(path, n.child), proofs)
+	}
+	groups := make([][]*entry, 16)
+	for _, target := range targets {
+		if len(target.key) == depth {
+			// Keys of index tries never end in branch node
+			return fmt.Errorf("%w: index %d", ErrKeyNotFound, target.index)
+		}
+		groups[target.key[depth]] = append(groups[target.key[depth]], target)
+	}
+	for nibble, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		child := n.children[nibble]
+		if child == nil {
+			return fmt.Errorf("%w: index %d", ErrKeyNotFound, group[0].index)
+		}
+		err := collectProofs(child, depth+1, group, appendNode(path, child), proofs)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addProof(target *entry, path [][]byte, proofs map[uint]*Proof) error {
+	nodes := make([]rlp.RawValue, len(path))
+	for i, enc := range path {
+		nodes[i] = enc
+	}
+	encoded, err := rlp.EncodeToBytes(nodes)
+	if err != nil {
+		return err
+	}
+	proofs[target.index] = &Proof{Key: target.key, Nodes: encoded}
+	return nil
+}
+
+// appendNode returns copy of path extended with n if it is referenced by hash
+func appendNode(path [][]byte, n *node) [][]byte {
+	if len(n.enc) < common.HashLength {
+		return path
+	}
+	res := make([][]byte, len(path), len(path)+1)
+	copy(res, path)
+	return append(res, n.enc)
+}
+
+// buildNode builds subtrie of sorted entries sharing first depth nibbles
+func buildNode(entries []*entry, depth int) (*node, error) {
+	if len(entries) == 1 {
+		n := &node{path: entries[0].key[depth:], leaf: true}
+		enc, err := rlp.EncodeToBytes([][]byte{hexToCompact(n.path, true), entries[0].value})
+		if err != nil {
+			return nil, err
+		}
+		n.enc = enc
+		return n, nil
+	}
+	// Entries are sorted so prefix of the first and the last one is shared by all of them
+	first, last := entries[0].key[depth:], entries[len(entries)-1].key[depth:]
+	prefix := 0
+	for prefix < len(first) && prefix < len(last) && first[prefix] == last[prefix] {
+		prefix++
+	}
+	if prefix == 0 {
+		return buildBranch(entries, depth)
+	}
+	child, err := buildBranch(entries, depth+prefix)
+	if err != nil {
+		return nil, err
+	}
+	n := &node{path: first[:prefix], child: child}
+	enc, err := rlp.EncodeToBytes([]interface{}{hexToCompact(n.path, false), reference(child)})
+	if err != nil {
+		return nil, err
+	}
+	n.enc = enc
+	return n, nil
+}
+
+func buildBranch(entries []*entry, depth int) (*node, error) {
+	n := &node{}
+	items := make([]interface{}, 17)
+	items[16] = []byte{}
+	start := 0
+	for start < len(entries) {
+		if len(entries[start].key) == depth {
+			return nil, fmt.Errorf("key of index %d is prefix of another key", entries[start].index)
+		}
+		nibble := entries[start].key[depth]
+		end := start + 1
+		for end < len(entries) && entries[end].key[depth] == nibble {
+			end++
+		}
+		child, err := buildNode(entries[start:end], depth+1)
+		if err != nil {
+			return nil, err
+		}
+		n.children[nibble] = child
+		start = end
+	}
+	for i, child := range n.children {
+		if child == nil {
+			items[i] = []byte{}
+			continue
+		}
+		items[i] = reference(child)
+	}
+	enc, err := rlp.EncodeToBytes(items)
+	if err != nil {
+		return nil, err
+	}
+	n.enc = enc
+	return n, nil
+}
+
+// reference returns how node is referenced by its parent, nodes shorter than hash are embedded
+func reference(n *node) interface{} {
+	if len(n.enc) < common.HashLength {
+		return rlp.RawValue(n.enc)
+	}
+	return crypto.Keccak256(n.enc)
+}
+
+// hexToCompact encodes nibbles with hex-prefix encoding flagging leaf and odd length paths
+func hexToCompact(hex []byte, leaf bool) []byte {
+	flag := byte(0)
+	if leaf {
+		flag = 2
+	}
+	buf := make([]byte, len(hex)/2+1)
+	if len(hex)&1 == 1 {
+		flag |= 1
+		buf[0] = flag<<4 | hex[0]
+		hex = hex[1:]
+	} else {
+		buf[0] = flag << 4
+	}
+	for i := 0; i < len(hex); i += 2 {
+		buf[i/2+1] = hex[i]<<4 | hex[i+1]
+	}
+	return buf
+}
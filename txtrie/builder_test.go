@@ -0,0 +1,135 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package txtrie
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// generateTransactions returns n transactions with varying payload so trie has embedded and hashed nodes
+func generateTransactions(n int) types.Transactions {
+	txs := make(types.Transactions, n)
+	for i := range txs {
+		txs[i] = types.NewTransaction(uint64(i), common.BigToAddress(big.NewInt(int64(i))), big.NewInt(int64(i)), 21000, big.NewInt(1), nil, nil, nil, bytes.Repeat([]byte{byte(i)}, i%64))
+	}
+	return txs
+}
+
+func assertSameProofs(t *testing.T, list types.DerivableList) {
+	reference, err := createTrie(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := NewTrie(reference.Hash(), list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexes := make([]uint, list.Len())
+	for i := range indexes {
+		indexes[i] = uint(i)
+	}
+	proofs, err := tr.ProveIndexes(indexes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range indexes {
+		keyRlp, err := rlp.EncodeToBytes(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes, key, err := RetrieveProof(reference, keyRlp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(nodes, proofs[i].Nodes) || !bytes.Equal(key, proofs[i].Key) {
+			t.Fatalf("proof of index %d differs from reference trie proof", i)
+		}
+	}
+}
+
+func TestTrieMatchesReferenceTrie(t *testing.T) {
+	assertSameProofs(t, GetTransactions1())
+	assertSameProofs(t, GetTransactions2())
+	assertSameProofs(t, GetTransactions3())
+	assertSameProofs(t, generateTransactions(1))
+	assertSameProofs(t, generateTransactions(300))
+}
+
+func TestTrieEmpty(t *testing.T) {
+	tr, err := NewTrie(types.EmptyRootHash, types.Transactions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tr.Prove(0)
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected key not found got %v", err)
+	}
+}
+
+func TestTrieRootMismatch(t *testing.T) {
+	_, err := NewTrie(common.Hash{1}, GetTransactions1())
+	if err == nil {
+		t.Fatal("expected trie roots mismatch")
+	}
+}
+
+func TestTrieProofVerifies(t *testing.T) {
+	txs := generateTransactions(200)
+	tr, err := NewTrie(types.DeriveSha(txs), txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := tr.Prove(150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Proofs are memoized
+	cached, err := tr.Prove(150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached != p {
+		t.Fatal("expected memoized proof")
+	}
+	tx, err := VerifyTxProof(&utils.MerkleProof{TxRootHash: tr.Hash(), Key: p.Key, Nodes: p.Nodes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Hash() != txs[150].Hash() {
+		t.Fatalf("expected tx %s got %s", txs[150].Hash().Hex(), tx.Hash().Hex())
+	}
+	_, err = tr.Prove(200)
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected key not found got %v", err)
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := NewCache(2)
+	tries := make([]*Trie, 3)
+	for i := range tries {
+		tries[i] = &Trie{}
+	}
+	c.Add(common.Hash{1}, tries[0])
+	c.Add(common.Hash{2}, tries[1])
+	// Access makes first trie the most recently used one
+	if tr, ok := c.Get(common.Hash{1}); !ok || tr != tries[0] {
+		t.Fatal("expected cached trie")
+	}
+	c.Add(common.Hash{3}, tries[2])
+	if c.Len() != 2 {
+		t.Fatalf("expected 2 cached tries got %d", c.Len())
+	}
+	if _, ok := c.Get(common.Hash{2}); ok {
+		t.Fatal("expected least recently used trie to be evicted")
+	}
+}
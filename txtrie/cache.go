@@ -0,0 +1,38 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package txtrie
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// Cache keeps least recently used block tries together with proofs already retrieved from them
+type Cache struct {
+	tries *lru.Cache
+}
+
+func NewCache(size int) *Cache {
+	tries, _ := lru.New(size)
+	return &Cache{tries: tries}
+}
+
+// Get returns trie built for block
+func (c *Cache) Get(block common.Hash) (*Trie, bool) {
+	t, ok := c.tries.Get(block)
+	if !ok {
+		return nil, false
+	}
+	return t.(*Trie), true
+}
+
+// Add stores trie built for block evicting least recently used one if cache is full
+func (c *Cache) Add(block common.Hash, t *Trie) {
+	c.tries.Add(block, t)
+}
+
+// Len returns number of cached tries
+func (c *Cache) Len() int {
+	return c.tries.Len()
+}
@@ -23,24 +23,29 @@ func CreateNewTrie(root common.Hash, transactions types.Transactions) (*ethtrie.
 	if transactions == nil {
 		return nil, errors.New("transactions cannot be nil")
 	}
+	trie, err := createTrie(transactions)
+	if err != nil {
+		return nil, err
+	}
+	if trie.Hash().Hex() != root.Hex() {
+		return nil, errors.New("transaction roots don't match")
+	}
+	return trie, nil
+}
+
+// createTrie inserts RLP encoded list items under RLP encoded index keys
+func createTrie(list types.DerivableList) (*ethtrie.Trie, error) {
 	db := memorydb.New()
 	trie, err := ethtrie.New(emptyRoot, ethtrie.NewDatabase(db))
 	if err != nil {
 		return nil, err
 	}
-	for i, tx := range transactions {
+	for i := 0; i < list.Len(); i++ {
 		key, err := rlp.EncodeToBytes(uint(i))
 		if err != nil {
 			return nil, err
 		}
-		value, err := rlp.EncodeToBytes(tx)
-		if err != nil {
-			return nil, err
-		}
-		trie.Update(key, value)
-	}
-	if trie.Hash().Hex() != root.Hex() {
-		return nil, errors.New("transaction roots don't match")
+		trie.Update(key, list.GetRlp(i))
 	}
 	return trie, nil
 }
@@ -0,0 +1,115 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package txtrie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var ErrInvalidProof = errors.New("invalid merkle proof")
+
+// VerifyProofNodes walks proof nodes in the format produced by RetrieveProof from root along key nibbles
+// and returns the value stored at the end of the path
+func VerifyProofNodes(root common.Hash, key []byte, nodes []byte) ([]byte, error) {
+	proof := make([][][]byte, 0)
+	err := rlp.DecodeBytes(nodes, &proof)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to decode nodes: %v", ErrInvalidProof, err)
+	}
+	for _, nibble := range key {
+		if nibble > 15 {
+			return nil, fmt.Errorf("%w: key is not a nibble path", ErrInvalidProof)
+		}
+	}
+	want := root.Bytes()
+	pos := 0
+	for i, n := range proof {
+		enc, err := rlp.EncodeToBytes(n)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(crypto.Keccak256(enc), want) {
+			return nil, fmt.Errorf("%w: node %d does not match hash %x", ErrInvalidProof, i, want)
+		}
+		last := i == len(proof)-1
+		switch len(n) {
+		case 17:
+			if pos == len(key) {
+				if !last || len(n[16]) == 0 {
+					return nil, fmt.Errorf("%w: no value at key", ErrInvalidProof)
+				}
+				return n[16], nil
+			}
+			want = n[key[pos]]
+			pos++
+			if len(want) == 0 {
+				return nil, fmt.Errorf("%w: no value at key", ErrInvalidProof)
+			}
+		case 2:
+			path, leaf := compactToHex(n[0])
+			if len(key)-pos < len(path) || !bytes.Equal(key[pos:pos+len(path)], path) {
+				return nil, fmt.Errorf("%w: key diverges from path at node %d", ErrInvalidProof, i)
+			}
+			pos += len(path)
+			if leaf {
+				if !last || pos != len(key) {
+					return nil, fmt.Errorf("%w: leaf does not terminate key", ErrInvalidProof)
+				}
+				return n[1], nil
+			}
+			want = n[1]
+		default:
+			return nil, fmt.Errorf("%w: node %d has %d elements", ErrInvalidProof, i, len(n))
+		}
+		if len(want) != common.HashLength {
+			return nil, fmt.Errorf("%w: embedded nodes are not supported", ErrInvalidProof)
+		}
+	}
+	return nil, fmt.Errorf("%w: proof ends before key", ErrInvalidProof)
+}
+
+// VerifyTxProof verifies transactions trie proof and returns proven transaction
+func VerifyTxProof(mp *utils.MerkleProof) (*types.Transaction, error) {
+	value, err := VerifyProofNodes(mp.TxRootHash, mp.Key, mp.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	tx := &types.Transaction{}
+	err = rlp.DecodeBytes(value, tx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode proven transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// KeyForIndex returns nibble path of tx index in transactions trie, as used in MerkleProof Key
+func KeyForIndex(index uint) ([]byte, error) {
+	keyRlp, err := rlp.EncodeToBytes(index)
+	if err != nil {
+		return nil, err
+	}
+	key := keybytesToHex(keyRlp)
+	return key[:len(key)-1], nil
+}
+
+// compactToHex decodes hex-prefix encoded node path into nibbles and reports whether it is a leaf path
+func compactToHex(compact []byte) ([]byte, bool) {
+	if len(compact) == 0 {
+		return compact, false
+	}
+	base := keybytesToHex(compact)
+	base = base[:len(base)-1]
+	leaf := base[0] >= 2
+	// odd length paths keep first nibble in the flag byte
+	chop := 2 - base[0]&1
+	return base[chop:], leaf
+}
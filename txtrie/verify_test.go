@@ -0,0 +1,77 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package txtrie
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-celo/utils"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func retrieveMerkleProof(t *testing.T, root [32]byte, list types.DerivableList, index uint) *utils.MerkleProof {
+	tr, err := createTrie(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRlp, err := rlp.EncodeToBytes(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, key, err := RetrieveProof(tr, keyRlp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &utils.MerkleProof{TxRootHash: root, Key: key, Nodes: nodes}
+}
+
+func TestVerifyTxProof(t *testing.T) {
+	txs := GetTransactions1()
+	root := types.DeriveSha(txs)
+	for i, expected := range txs {
+		mp := retrieveMerkleProof(t, root, txs, uint(i))
+		key, err := KeyForIndex(uint(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(key, mp.Key) {
+			t.Fatalf("expected key %x got %x", key, mp.Key)
+		}
+		tx, err := VerifyTxProof(mp)
+		if err != nil {
+			t.Fatalf("tx %d: %s", i, err)
+		}
+		if tx.Hash() != expected.Hash() {
+			t.Fatalf("expected tx %s got %s", expected.Hash().Hex(), tx.Hash().Hex())
+		}
+	}
+}
+
+func TestVerifyProofNodesInvalid(t *testing.T) {
+	txs := GetTransactions1()
+	root := types.DeriveSha(txs)
+	mp := retrieveMerkleProof(t, root, txs, 1)
+
+	_, err := VerifyProofNodes([32]byte{1}, mp.Key, mp.Nodes)
+	if !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected invalid proof for wrong root got %v", err)
+	}
+	otherKey, err := KeyForIndex(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = VerifyProofNodes(root, otherKey, mp.Nodes)
+	if !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected invalid proof for wrong key got %v", err)
+	}
+	tampered := append([]byte{}, mp.Nodes...)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = VerifyProofNodes(root, mp.Key, tampered)
+	if !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected invalid proof for tampered nodes got %v", err)
+	}
+}
@@ -4,10 +4,12 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 type ChainId uint8
@@ -18,6 +20,16 @@ func (r *ResourceId) Hex() string {
 	return fmt.Sprintf("%x", r)
 }
 
+// MarshalText encodes resource id as 0x prefixed hex string
+func (r ResourceId) MarshalText() ([]byte, error) {
+	return []byte(hexutil.Encode(r[:])), nil
+}
+
+// UnmarshalText decodes resource id from 0x prefixed hex string
+func (r *ResourceId) UnmarshalText(input []byte) error {
+	return hexutil.UnmarshalFixedText("ResourceId", input, r[:])
+}
+
 type Nonce uint64
 
 func (n Nonce) Big() *big.Int {
@@ -100,3 +112,57 @@ func NewGenericTransfer(source, dest ChainId, nonce Nonce, resourceId ResourceId
 		},
 	}
 }
+
+type jsonMessage struct {
+	Source       ChainId                `json:"source"`
+	Destination  ChainId                `json:"destination"`
+	Type         TransferType           `json:"type"`
+	DepositNonce Nonce                  `json:"depositNonce"`
+	ResourceId   ResourceId             `json:"resourceId"`
+	MPParams     *MerkleProof           `json:"mpParams,omitempty"`
+	SVParams     *SignatureVerification `json:"svParams,omitempty"`
+	Payload      []hexutil.Bytes        `json:"payload"`
+}
+
+// MarshalJSON encodes message with payload elements as hex strings. Every payload element is expected to be []byte
+func (m *Message) MarshalJSON() ([]byte, error) {
+	payload := make([]hexutil.Bytes, len(m.Payload))
+	for i, p := range m.Payload {
+		b, ok := p.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unsupported payload element type %T", p)
+		}
+		payload[i] = b
+	}
+	return json.Marshal(&jsonMessage{
+		Source:       m.Source,
+		Destination:  m.Destination,
+		Type:         m.Type,
+		DepositNonce: m.DepositNonce,
+		ResourceId:   m.ResourceId,
+		MPParams:     m.MPParams,
+		SVParams:     m.SVParams,
+		Payload:      payload,
+	})
+}
+
+// UnmarshalJSON decodes message encoded by MarshalJSON
+func (m *Message) UnmarshalJSON(input []byte) error {
+	dec := &jsonMessage{}
+	err := json.Unmarshal(input, dec)
+	if err != nil {
+		return err
+	}
+	m.Source = dec.Source
+	m.Destination = dec.Destination
+	m.Type = dec.Type
+	m.DepositNonce = dec.DepositNonce
+	m.ResourceId = dec.ResourceId
+	m.MPParams = dec.MPParams
+	m.SVParams = dec.SVParams
+	m.Payload = make([]interface{}, len(dec.Payload))
+	for i, p := range dec.Payload {
+		m.Payload[i] = []byte(p)
+	}
+	return nil
+}
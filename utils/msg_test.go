@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestMessageJSONRoundTrip(t *testing.T) {
+	m := NewNonFungibleTransfer(1, 2, 3, ResourceId{4}, &MerkleProof{TxRootHash: [32]byte{5}, Key: []byte{6}, Nodes: []byte{7}}, &SignatureVerification{AggregatePublicKey: []byte{8}, BlockHash: common.Hash{9}, Signature: []byte{10}}, big.NewInt(11), []byte{12}, []byte{13})
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &Message{}
+	err = json.Unmarshal(data, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, decoded) {
+		t.Fatalf("expected %+v got %+v", m, decoded)
+	}
+}
+
+func TestMessageJSONUnsupportedPayload(t *testing.T) {
+	m := &Message{Payload: []interface{}{"string"}}
+	_, err := json.Marshal(m)
+	if err == nil {
+		t.Fatal("expected unsupported payload error got nil")
+	}
+}
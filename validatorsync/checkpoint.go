@@ -0,0 +1,133 @@
+//Copyright 2020 ChainSafe Systems
+//SPDX-License-Identifier: LGPL-3.0-only
+package validatorsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrStoreBehindCheckpoint = errors.New("validators store is behind checkpoint, seeding would leave unsynced epochs")
+var ErrStartBeforeCheckpoint = errors.New("listener start block is before validators checkpoint")
+
+// Checkpoint is a trusted set of validators that signed epoch last block Block
+type Checkpoint struct {
+	Block      *big.Int                  `json:"block"`
+	Validators []*istanbul.ValidatorData `json:"validators"`
+}
+
+// ParseCheckpoint parses checkpoint either inline as `block@address:blsPublicKey,...` or from JSON snapshot file at provided path
+func ParseCheckpoint(raw string) (*Checkpoint, error) {
+	if !strings.Contains(raw, "@") {
+		return LoadCheckpoint(raw)
+	}
+	parts := strings.SplitN(raw, "@", 2)
+	block, ok := new(big.Int).SetString(parts[0], 10)
+	if !ok {
+		return nil, fmt.Errorf("unable to parse checkpoint block %s", parts[0])
+	}
+	cp := &Checkpoint{Block: block, Validators: make([]*istanbul.ValidatorData, 0)}
+	for _, entry := range strings.Split(parts[1], ",") {
+		fields := strings.Split(strings.TrimSpace(entry), ":")
+		if len(fields) != 2 || !common.IsHexAddress(fields[0]) {
+			return nil, fmt.Errorf("malformed checkpoint validator %s, expected address:blsPublicKey", entry)
+		}
+		v := &istanbul.ValidatorData{Address: common.HexToAddress(fields[0])}
+		err := v.BLSPublicKey.UnmarshalText([]byte(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid BLS public key of validator %s: %w", fields[0], err)
+		}
+		cp.Validators = append(cp.Validators, v)
+	}
+	return cp, nil
+}
+
+// LoadCheckpoint reads checkpoint from JSON snapshot file
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cp := &Checkpoint{}
+	err = json.Unmarshal(data, cp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse checkpoint file %s: %w", path, err)
+	}
+	if cp.Block == nil || len(cp.Validators) == 0 {
+		return nil, fmt.Errorf("checkpoint file %s should contain block and validators", path)
+	}
+	return cp, nil
+}
+
+// SeedCheckpoint verifies that checkpoint validators signed checkpoint block and stores validators resulting from its diff,
+// so syncing starts from checkpoint. Store that already knows checkpoint block is left untouched, non-empty store behind
+// checkpoint is refused as epochs between its latest one and checkpoint would never be synced.
+func SeedCheckpoint(c HeaderByNumberGetter, db *ValidatorsStore, chainID uint8, epochSize uint64, cp *Checkpoint) error {
+	if !istanbul.IsLastBlockOfEpoch(cp.Block.Uint64(), epochSize) {
+		return fmt.Errorf("checkpoint block %s is not last block of epoch", cp.Block.String())
+	}
+	latest, err := db.GetLatestKnownEpochLastBlock(chainID)
+	if err != nil {
+		return err
+	}
+	if latest.Cmp(cp.Block) >= 0 {
+		log.Info().Str("checkpoint", cp.Block.String()).Str("latest", latest.String()).Msg("Validators store is ahead of checkpoint, skipping")
+		return nil
+	}
+	epochs, err := db.Epochs(chainID)
+	if err != nil {
+		return err
+	}
+	if len(epochs) > 0 {
+		return fmt.Errorf("%w: chain %d latest stored %s, checkpoint %s", ErrStoreBehindCheckpoint, chainID, latest, cp.Block)
+	}
+	header, err := c.HeaderByNumber(context.Background(), cp.Block)
+	if err != nil {
+		return fmt.Errorf("gettings checkpoint header err: %w", err)
+	}
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return fmt.Errorf("error on extracting istanbul extra: %w", err)
+	}
+	err = verifyAggregatedSeal(header, extra.AggregatedSeal, cp.Validators)
+	if err != nil {
+		return fmt.Errorf("checkpoint validators did not sign block %s: %w", cp.Block.String(), err)
+	}
+	validators, err := applyValidatorsDiff(extra, cp.Validators)
+	if err != nil {
+		return fmt.Errorf("error applying validators diff: %w", err)
+	}
+	err = db.SetValidatorsForBlock(cp.Block, validators, chainID)
+	if err != nil {
+		return err
+	}
+	log.Info().Str("block", cp.Block.String()).Int("validators", len(validators)).Msg("Validators store seeded from checkpoint")
+	return nil
+}
+
+// CheckStartBlock returns ErrStartBeforeCheckpoint if listener starting at start block would need validators of epochs
+// before checkpoint that are not stored and are never synced
+func CheckStartBlock(db *ValidatorsStore, chainID uint8, cp *Checkpoint, start *big.Int) error {
+	if start.Cmp(cp.Block) > 0 {
+		return nil
+	}
+	epochs, err := db.Epochs(chainID)
+	if err != nil {
+		return err
+	}
+	// Validators of the first stored epoch verify blocks after it
+	if len(epochs) > 0 && epochs[0].Cmp(start) < 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: chain %d starts at %s, checkpoint %s", ErrStartBeforeCheckpoint, chainID, start, cp.Block)
+}
@@ -0,0 +1,287 @@
+//Copyright 2020 ChainSafe Systems
+//SPDX-License-Identifier: LGPL-3.0-only
+package validatorsync
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-celo/validatorsync/mock"
+	"github.com/celo-org/celo-bls-go/bls"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	istanbulCore "github.com/ethereum/go-ethereum/consensus/istanbul/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	blscrypto "github.com/ethereum/go-ethereum/crypto/bls"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type testValidator struct {
+	data       *istanbul.ValidatorData
+	privateKey []byte
+}
+
+func newTestValidator(i int) (*testValidator, error) {
+	key, err := crypto.HexToECDSA(fmt.Sprintf("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f%03x", i))
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := blscrypto.ECDSAToBLS(key)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := blscrypto.PrivateToPublic(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &testValidator{
+		data:       &istanbul.ValidatorData{Address: crypto.PubkeyToAddress(key.PublicKey), BLSPublicKey: publicKey},
+		privateKey: privateKey,
+	}, nil
+}
+
+func newTestValidators(from, count int) ([]*testValidator, error) {
+	validators := make([]*testValidator, 0, count)
+	for i := from; i < from+count; i++ {
+		v, err := newTestValidator(i)
+		if err != nil {
+			return nil, err
+		}
+		validators = append(validators, v)
+	}
+	return validators, nil
+}
+
+func validatorsData(validators []*testValidator) []*istanbul.ValidatorData {
+	data := make([]*istanbul.ValidatorData, len(validators))
+	for i, v := range validators {
+		data[i] = v.data
+	}
+	return data
+}
+
+// generateSignedHeader creates header of block number that adds validators and is signed by signers set in bitmap.
+// Header without signers is left unsealed as genesis block is
+func generateSignedHeader(number uint64, added []*testValidator, signers []*testValidator, bitmap *big.Int) (*types.Header, error) {
+	istExtra := &types.IstanbulExtra{
+		AddedValidators:           make([]common.Address, 0),
+		AddedValidatorsPublicKeys: make([]blscrypto.SerializedPublicKey, 0),
+		RemovedValidators:         big.NewInt(0),
+		Seal:                      []byte{},
+		AggregatedSeal:            types.IstanbulAggregatedSeal{},
+		ParentAggregatedSeal:      types.IstanbulAggregatedSeal{},
+	}
+	for _, v := range added {
+		istExtra.AddedValidators = append(istExtra.AddedValidators, v.data.Address)
+		istExtra.AddedValidatorsPublicKeys = append(istExtra.AddedValidatorsPublicKeys, v.data.BLSPublicKey)
+	}
+	extra, err := rlp.EncodeToBytes(istExtra)
+	if err != nil {
+		return nil, err
+	}
+	h := &types.Header{
+		Number: new(big.Int).SetUint64(number),
+		Extra:  append(make([]byte, types.IstanbulExtraVanity), extra...),
+	}
+	if len(signers) == 0 {
+		return h, nil
+	}
+	round := big.NewInt(0)
+	msg := istanbulCore.PrepareCommittedSeal(h.Hash(), round)
+	signatures := make([]*bls.Signature, 0)
+	for i, v := range signers {
+		if bitmap.Bit(i) == 0 {
+			continue
+		}
+		privateKey, err := bls.DeserializePrivateKey(v.privateKey)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := privateKey.SignMessage(msg, []byte{}, false)
+		privateKey.Destroy()
+		if err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, sig)
+	}
+	aggregated, err := bls.AggregateSignatures(signatures)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := aggregated.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	istExtra.AggregatedSeal = types.IstanbulAggregatedSeal{Bitmap: bitmap, Signature: signature, Round: round}
+	extra, err = rlp.EncodeToBytes(istExtra)
+	if err != nil {
+		return nil, err
+	}
+	h.Extra = append(make([]byte, types.IstanbulExtraVanity), extra...)
+	return h, nil
+}
+
+type CheckpointTestSuite struct {
+	suite.Suite
+	store  *ValidatorsStore
+	client *mock_validatorsync.MockHeaderByNumberGetter
+}
+
+func TestRunCheckpointTestSuite(t *testing.T) {
+	suite.Run(t, new(CheckpointTestSuite))
+}
+func (s *CheckpointTestSuite) SetupSuite()    {}
+func (s *CheckpointTestSuite) TearDownSuite() {}
+func (s *CheckpointTestSuite) SetupTest() {
+	gomockController := gomock.NewController(s.T())
+	s.store = NewValidatorsStoreWithStorage(NewMemoryStorage())
+	s.client = mock_validatorsync.NewMockHeaderByNumberGetter(gomockController)
+}
+func (s *CheckpointTestSuite) TearDownTest() {
+	s.store.Close()
+	os.RemoveAll("./test")
+}
+
+func (s *CheckpointTestSuite) TestParseInlineCheckpoint() {
+	validators, err := newTestValidators(1, 2)
+	s.Nil(err)
+	key1, err := validators[0].data.BLSPublicKey.MarshalText()
+	s.Nil(err)
+	key2, err := validators[1].data.BLSPublicKey.MarshalText()
+	s.Nil(err)
+	raw := fmt.Sprintf("120@%s:%s, %s:%s", validators[0].data.Address.Hex(), key1, validators[1].data.Address.Hex(), key2)
+
+	cp, err := ParseCheckpoint(raw)
+	s.Nil(err)
+	s.Equal(0, cp.Block.Cmp(big.NewInt(120)))
+	s.Equal(validatorsData(validators), cp.Validators)
+}
+
+func (s *CheckpointTestSuite) TestParseMalformedInlineCheckpoint() {
+	_, err := ParseCheckpoint("abc@0x44add0ec310f115a0e603b2d7db9f067778eaf8a:0x01")
+	s.NotNil(err)
+	_, err = ParseCheckpoint("120@0x44add0ec310f115a0e603b2d7db9f067778eaf8a")
+	s.NotNil(err)
+	_, err = ParseCheckpoint("120@0x44add0ec310f115a0e603b2d7db9f067778eaf8a:0x01")
+	s.NotNil(err)
+}
+
+func (s *CheckpointTestSuite) TestParseCheckpointFile() {
+	validators, err := newTestValidators(1, 2)
+	s.Nil(err)
+	key1, err := validators[0].data.BLSPublicKey.MarshalText()
+	s.Nil(err)
+	key2, err := validators[1].data.BLSPublicKey.MarshalText()
+	s.Nil(err)
+	s.Nil(os.MkdirAll("./test", 0700))
+	path := "./test/checkpoint.json"
+	content := fmt.Sprintf(`{"block":120,"validators":[{"Address":"%s","BLSPublicKey":"%s"},{"Address":"%s","BLSPublicKey":"%s"}]}`,
+		validators[0].data.Address.Hex(), key1, validators[1].data.Address.Hex(), key2)
+	s.Nil(ioutil.WriteFile(path, []byte(content), 0600))
+
+	cp, err := ParseCheckpoint(path)
+	s.Nil(err)
+	s.Equal(0, cp.Block.Cmp(big.NewInt(120)))
+	s.Equal(validatorsData(validators), cp.Validators)
+
+	s.Nil(ioutil.WriteFile(path, []byte(`{"block":120}`), 0600))
+	_, err = ParseCheckpoint(path)
+	s.NotNil(err)
+}
+
+func (s *CheckpointTestSuite) TestSeedCheckpoint() {
+	chainID := uint8(1)
+	signers, err := newTestValidators(1, 3)
+	s.Nil(err)
+	added, err := newTestValidators(4, 1)
+	s.Nil(err)
+	header, err := generateSignedHeader(24, added, signers, big.NewInt(7))
+	s.Nil(err)
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(24)).Return(header, nil)
+
+	err = SeedCheckpoint(s.client, s.store, chainID, 12, &Checkpoint{Block: big.NewInt(24), Validators: validatorsData(signers)})
+	s.Nil(err)
+
+	vals, err := s.store.GetValidatorsForBlock(big.NewInt(24), chainID)
+	s.Nil(err)
+	s.Equal(append(validatorsData(signers), added[0].data), vals)
+	lb, err := s.store.GetLatestKnownEpochLastBlock(chainID)
+	s.Nil(err)
+	s.Equal(0, lb.Cmp(big.NewInt(24)))
+}
+
+func (s *CheckpointTestSuite) TestSeedCheckpointInsufficientSeals() {
+	signers, err := newTestValidators(1, 3)
+	s.Nil(err)
+	header, err := generateSignedHeader(24, nil, signers, big.NewInt(1))
+	s.Nil(err)
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(24)).Return(header, nil)
+
+	err = SeedCheckpoint(s.client, s.store, 1, 12, &Checkpoint{Block: big.NewInt(24), Validators: validatorsData(signers)})
+	s.True(errors.Is(err, ErrInsufficientSeals))
+}
+
+func (s *CheckpointTestSuite) TestSeedCheckpointWrongValidators() {
+	signers, err := newTestValidators(1, 3)
+	s.Nil(err)
+	others, err := newTestValidators(5, 3)
+	s.Nil(err)
+	header, err := generateSignedHeader(24, nil, signers, big.NewInt(7))
+	s.Nil(err)
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(24)).Return(header, nil)
+
+	err = SeedCheckpoint(s.client, s.store, 1, 12, &Checkpoint{Block: big.NewInt(24), Validators: validatorsData(others)})
+	s.NotNil(err)
+	lb, err := s.store.GetLatestKnownEpochLastBlock(1)
+	s.Nil(err)
+	s.Equal(0, lb.Cmp(big.NewInt(0)))
+}
+
+func (s *CheckpointTestSuite) TestSeedCheckpointNotEpochLastBlock() {
+	err := SeedCheckpoint(s.client, s.store, 1, 12, &Checkpoint{Block: big.NewInt(20)})
+	s.NotNil(err)
+}
+
+func (s *CheckpointTestSuite) TestSeedCheckpointStoreAhead() {
+	chainID := uint8(1)
+	signers, err := newTestValidators(1, 3)
+	s.Nil(err)
+	s.Nil(s.store.SetValidatorsForBlock(big.NewInt(36), validatorsData(signers), chainID))
+
+	// No header is requested as store already knows later block
+	err = SeedCheckpoint(s.client, s.store, chainID, 12, &Checkpoint{Block: big.NewInt(24), Validators: validatorsData(signers)})
+	s.Nil(err)
+}
+
+func (s *CheckpointTestSuite) TestSeedCheckpointStoreBehind() {
+	chainID := uint8(1)
+	signers, err := newTestValidators(1, 3)
+	s.Nil(err)
+	s.Nil(s.store.SetValidatorsForBlock(big.NewInt(12), validatorsData(signers), chainID))
+
+	err = SeedCheckpoint(s.client, s.store, chainID, 12, &Checkpoint{Block: big.NewInt(36), Validators: validatorsData(signers)})
+	s.True(errors.Is(err, ErrStoreBehindCheckpoint))
+}
+
+func (s *CheckpointTestSuite) TestCheckStartBlock() {
+	chainID := uint8(1)
+	signers, err := newTestValidators(1, 3)
+	s.Nil(err)
+	cp := &Checkpoint{Block: big.NewInt(24), Validators: validatorsData(signers)}
+	s.Nil(s.store.SetValidatorsForBlock(big.NewInt(24), validatorsData(signers), chainID))
+
+	s.Nil(CheckStartBlock(s.store, chainID, cp, big.NewInt(25)))
+	s.True(errors.Is(CheckStartBlock(s.store, chainID, cp, big.NewInt(24)), ErrStartBeforeCheckpoint))
+	s.True(errors.Is(CheckStartBlock(s.store, chainID, cp, big.NewInt(10)), ErrStartBeforeCheckpoint))
+
+	// Store synced from earlier epochs covers start block
+	s.Nil(s.store.SetValidatorsForBlock(big.NewInt(0), validatorsData(signers), chainID))
+	s.Nil(CheckStartBlock(s.store, chainID, cp, big.NewInt(10)))
+}
@@ -0,0 +1,145 @@
+//Copyright 2020 ChainSafe Systems
+//SPDX-License-Identifier: LGPL-3.0-only
+package validatorsync
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// LevelDBStorage is ValidatorsStorage of LevelDB database shared with other relayer stores. Key layout is described in schema.go
+type LevelDBStorage struct {
+	db *leveldb.DB
+}
+
+func NewLevelDBStorage(db *leveldb.DB) *LevelDBStorage {
+	return &LevelDBStorage{db: db}
+}
+
+func (s *LevelDBStorage) LatestEpoch(chainID uint8) (*big.Int, error) {
+	data, err := s.db.Get(latestKey(chainID), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return big.NewInt(0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+func (s *LevelDBStorage) PutEpoch(chainID uint8, block *big.Int, validators []*istanbul.ValidatorData, apk []byte) error {
+	byteValidators, err := rlp.EncodeToBytes(validators)
+	if err != nil {
+		return err
+	}
+	batch := new(leveldb.Batch)
+	if apk != nil {
+		batch.Put(apkKey(block, chainID), apk)
+	}
+	batch.Put(validatorsKey(block, chainID), byteValidators)
+	batch.Put(latestKey(chainID), encodeBlock(block))
+	return s.db.Write(batch, nil)
+}
+
+func (s *LevelDBStorage) Validators(chainID uint8, block *big.Int) ([]*istanbul.ValidatorData, error) {
+	res, err := s.get(validatorsKey(block, chainID))
+	if err != nil {
+		return nil, err
+	}
+	validators := make([]*istanbul.ValidatorData, 0)
+	err = rlp.DecodeBytes(res, &validators)
+	if err != nil {
+		return nil, err
+	}
+	return validators, nil
+}
+
+func (s *LevelDBStorage) APK(chainID uint8, block *big.Int) ([]byte, error) {
+	return s.get(apkKey(block, chainID))
+}
+
+func (s *LevelDBStorage) PutAPK(chainID uint8, block *big.Int, apk []byte) error {
+	return s.db.Put(apkKey(block, chainID), apk, nil)
+}
+
+func (s *LevelDBStorage) Chains() ([]uint8, error) {
+	chains := make([]uint8, 0)
+	iter := s.db.NewIterator(util.BytesPrefix([]byte(latestKeyPrefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) == len(latestKeyPrefix)+1 {
+			chains = append(chains, key[len(key)-1])
+		}
+	}
+	return chains, iter.Error()
+}
+
+func (s *LevelDBStorage) Epochs(chainID uint8) ([]*big.Int, error) {
+	prefix := append([]byte(validatorsKeyPrefix), chainID)
+	epochs := make([]*big.Int, 0)
+	iter := s.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		epochs = append(epochs, new(big.Int).SetBytes(iter.Key()[len(prefix):]))
+	}
+	return epochs, iter.Error()
+}
+
+// DeleteEpochs compacts key ranges of deleted epochs so disk space is freed
+func (s *LevelDBStorage) DeleteEpochs(chainID uint8, before *big.Int) (int, int64, error) {
+	ranges := []*util.Range{
+		{Start: validatorsKey(big.NewInt(0), chainID), Limit: validatorsKey(before, chainID)},
+		{Start: apkKey(big.NewInt(0), chainID), Limit: apkKey(before, chainID)},
+	}
+	batch := new(leveldb.Batch)
+	deleted := 0
+	var freed int64
+	for i, r := range ranges {
+		iter := s.db.NewIterator(r, nil)
+		for iter.Next() {
+			key := iter.Key()
+			batch.Delete(append([]byte{}, key...))
+			freed += int64(len(key) + len(iter.Value()))
+			if i == 0 {
+				deleted++
+			}
+		}
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			return 0, 0, err
+		}
+	}
+	if batch.Len() == 0 {
+		return 0, 0, nil
+	}
+	err := s.db.Write(batch, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, r := range ranges {
+		err = s.db.CompactRange(*r)
+		if err != nil {
+			return deleted, freed, err
+		}
+	}
+	return deleted, freed, nil
+}
+
+func (s *LevelDBStorage) Close() error {
+	return s.db.Close()
+}
+
+// get returns value of key translating missing key to ErrEpochNotFound
+func (s *LevelDBStorage) get(key []byte) ([]byte, error) {
+	data, err := s.db.Get(key, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, ErrEpochNotFound
+	}
+	return data, err
+}
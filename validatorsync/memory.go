@@ -0,0 +1,163 @@
+//Copyright 2020 ChainSafe Systems
+//SPDX-License-Identifier: LGPL-3.0-only
+package validatorsync
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// MemoryStorage is ValidatorsStorage kept in memory, eg. for tests and relayers without persistent validators history
+type MemoryStorage struct {
+	chains map[uint8]*memoryChain
+	lock   sync.RWMutex
+}
+
+type memoryChain struct {
+	latest     uint64
+	hasLatest  bool
+	validators map[uint64][]byte // RLP encoded validators by epoch last block
+	apks       map[uint64][]byte
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{chains: make(map[uint8]*memoryChain)}
+}
+
+func (s *MemoryStorage) LatestEpoch(chainID uint8) (*big.Int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	c, ok := s.chains[chainID]
+	if !ok || !c.hasLatest {
+		return big.NewInt(0), nil
+	}
+	return new(big.Int).SetUint64(c.latest), nil
+}
+
+func (s *MemoryStorage) PutEpoch(chainID uint8, block *big.Int, validators []*istanbul.ValidatorData, apk []byte) error {
+	data, err := rlp.EncodeToBytes(validators)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	c := s.chain(chainID)
+	if apk != nil {
+		c.apks[block.Uint64()] = append([]byte{}, apk...)
+	}
+	c.validators[block.Uint64()] = data
+	c.latest = block.Uint64()
+	c.hasLatest = true
+	return nil
+}
+
+func (s *MemoryStorage) Validators(chainID uint8, block *big.Int) ([]*istanbul.ValidatorData, error) {
+	s.lock.RLock()
+	c, ok := s.chains[chainID]
+	var data []byte
+	if ok {
+		data, ok = c.validators[block.Uint64()]
+	}
+	s.lock.RUnlock()
+	if !ok {
+		return nil, ErrEpochNotFound
+	}
+	validators := make([]*istanbul.ValidatorData, 0)
+	err := rlp.DecodeBytes(data, &validators)
+	if err != nil {
+		return nil, err
+	}
+	return validators, nil
+}
+
+func (s *MemoryStorage) APK(chainID uint8, block *big.Int) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	c, ok := s.chains[chainID]
+	if !ok {
+		return nil, ErrEpochNotFound
+	}
+	apk, ok := c.apks[block.Uint64()]
+	if !ok {
+		return nil, ErrEpochNotFound
+	}
+	return append([]byte{}, apk...), nil
+}
+
+func (s *MemoryStorage) PutAPK(chainID uint8, block *big.Int, apk []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.chain(chainID).apks[block.Uint64()] = append([]byte{}, apk...)
+	return nil
+}
+
+func (s *MemoryStorage) Chains() ([]uint8, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	chains := make([]uint8, 0, len(s.chains))
+	for id, c := range s.chains {
+		if c.hasLatest {
+			chains = append(chains, id)
+		}
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i] < chains[j] })
+	return chains, nil
+}
+
+func (s *MemoryStorage) Epochs(chainID uint8) ([]*big.Int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	epochs := make([]*big.Int, 0)
+	c, ok := s.chains[chainID]
+	if !ok {
+		return epochs, nil
+	}
+	for block := range c.validators {
+		epochs = append(epochs, new(big.Int).SetUint64(block))
+	}
+	sort.Slice(epochs, func(i, j int) bool { return epochs[i].Cmp(epochs[j]) < 0 })
+	return epochs, nil
+}
+
+func (s *MemoryStorage) DeleteEpochs(chainID uint8, before *big.Int) (int, int64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	c, ok := s.chains[chainID]
+	if !ok {
+		return 0, 0, nil
+	}
+	deleted := 0
+	var freed int64
+	for block, data := range c.validators {
+		if block < before.Uint64() {
+			delete(c.validators, block)
+			freed += int64(len(data))
+			deleted++
+		}
+	}
+	for block, apk := range c.apks {
+		if block < before.Uint64() {
+			delete(c.apks, block)
+			freed += int64(len(apk))
+		}
+	}
+	return deleted, freed, nil
+}
+
+func (s *MemoryStorage) Close() error {
+	return nil
+}
+
+// chain should be called under lock
+func (s *MemoryStorage) chain(chainID uint8) *memoryChain {
+	c, ok := s.chains[chainID]
+	if !ok {
+		c = &memoryChain{validators: make(map[uint64][]byte), apks: make(map[uint64][]byte)}
+		s.chains[chainID] = c
+	}
+	return c
+}
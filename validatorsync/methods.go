@@ -3,10 +3,12 @@
 package validatorsync
 
 import (
+	"math"
 	"math/big"
 
 	"github.com/celo-org/celo-bls-go/bls"
 	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	istanbulCore "github.com/ethereum/go-ethereum/consensus/istanbul/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto/bls"
 	"github.com/pkg/errors"
@@ -14,6 +16,9 @@ import (
 
 var (
 	ErrorWrongInitialValidators = errors.New("wrong initial validators")
+	ErrInvalidAggregatedSeal    = errors.New("invalid aggregated seal")
+	ErrInsufficientSeals        = errors.New("aggregated seal does not aggregate enough seals")
+	ErrInvalidSignature         = errors.New("invalid aggregated seal signature")
 )
 
 func applyValidatorsDiff(extra *types.IstanbulExtra, validators []*istanbul.ValidatorData) ([]*istanbul.ValidatorData, error) {
@@ -40,20 +45,21 @@ func applyValidatorsDiff(extra *types.IstanbulExtra, validators []*istanbul.Vali
 	return newValidators, nil
 }
 
-func aggregatePublicKeys(validators []*istanbul.ValidatorData) (*bls.PublicKey, error) {
-	publicKeys := make([]blscrypto.SerializedPublicKey, len(validators))
+// aggregatePublicKeys returns serialized aggregated BLS public key of validators
+func aggregatePublicKeys(validators []*istanbul.ValidatorData) ([]byte, error) {
+	publicKeyObjs := make([]*bls.PublicKey, 0, len(validators))
+	// Keys are destroyed only after aggregated key is serialized
+	defer func() {
+		for _, publicKeyObj := range publicKeyObjs {
+			publicKeyObj.Destroy()
+		}
+	}()
 	for i := range validators {
-		publicKeys[i] = validators[i].BLSPublicKey
-	}
-
-	publicKeyObjs := make([]*bls.PublicKey, len(publicKeys))
-	for i := range publicKeys {
-		publicKeyObj, err := bls.DeserializePublicKeyCached(publicKeys[i][:])
+		publicKeyObj, err := bls.DeserializePublicKeyCached(validators[i].BLSPublicKey[:])
 		if err != nil {
 			return nil, err
 		}
-		publicKeyObjs[i] = publicKeyObj
-		publicKeyObj.Destroy()
+		publicKeyObjs = append(publicKeyObjs, publicKeyObj)
 	}
 	apk, err := bls.AggregatePublicKeys(publicKeyObjs)
 	if err != nil {
@@ -61,7 +67,7 @@ func aggregatePublicKeys(validators []*istanbul.ValidatorData) (*bls.PublicKey,
 	}
 	defer apk.Destroy()
 
-	return apk, nil
+	return apk.Serialize()
 }
 
 func computeLastBlockOfEpochForProvidedBlock(block *big.Int, epochSize uint64) *big.Int {
@@ -69,3 +75,29 @@ func computeLastBlockOfEpochForProvidedBlock(block *big.Int, epochSize uint64) *
 	lastBlock := istanbul.GetEpochLastBlockNumber(epochNumber, epochSize)
 	return big.NewInt(0).SetUint64(lastBlock)
 }
+
+// verifyAggregatedSeal checks that header was signed by a quorum of validators same way istanbul consensus does
+func verifyAggregatedSeal(header *types.Header, seal types.IstanbulAggregatedSeal, validators []*istanbul.ValidatorData) error {
+	if len(seal.Signature) != types.IstanbulExtraBlsSignature || seal.Bitmap == nil || seal.Round == nil {
+		return ErrInvalidAggregatedSeal
+	}
+	publicKeys := make([]blscrypto.SerializedPublicKey, 0)
+	for i := range validators {
+		if seal.Bitmap.Bit(i) == 1 {
+			publicKeys = append(publicKeys, validators[i].BLSPublicKey)
+		}
+	}
+	if len(publicKeys) == 0 || len(publicKeys) < minQuorumSize(len(validators)) {
+		return ErrInsufficientSeals
+	}
+	proposalSeal := istanbulCore.PrepareCommittedSeal(header.Hash(), seal.Round)
+	err := blscrypto.VerifyAggregatedSignature(publicKeys, proposalSeal, []byte{}, seal.Signature, false)
+	if err != nil {
+		return errors.Wrap(ErrInvalidSignature, err.Error())
+	}
+	return nil
+}
+
+func minQuorumSize(validatorsCount int) int {
+	return int(math.Ceil(float64(2*validatorsCount) / 3))
+}
@@ -0,0 +1,104 @@
+//Copyright 2020 ChainSafe Systems
+//SPDX-License-Identifier: LGPL-3.0-only
+package validatorsync
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Interval between validators history pruning runs
+var PruneInterval = time.Hour
+
+// BlockstoreReader provides block the listener of chain continues from
+type BlockstoreReader interface {
+	TryLoadLatestBlock() (*big.Int, error)
+}
+
+// Retention is validators history retention policy. Epoch the listener continues from and later epochs are never
+// pruned, as is the latest stored epoch validators sync continues from.
+type Retention struct {
+	Epochs uint64   // Number of latest epochs kept, 0 keeps all
+	Margin *big.Int // Number of blocks kept before blockstore checkpoint, nil means no margin before the checkpoint
+}
+
+// Cutoff returns epoch last block epochs before which are pruned
+func (r *Retention) Cutoff(latest, checkpoint *big.Int, epochSize uint64) *big.Int {
+	if r.Epochs == 0 && r.Margin == nil {
+		return big.NewInt(0)
+	}
+	from := new(big.Int).Set(checkpoint)
+	if r.Margin != nil {
+		from.Sub(from, r.Margin)
+	}
+	if from.Sign() < 0 {
+		from.SetUint64(0)
+	}
+	cutoff := computeLastBlockOfEpochForProvidedBlock(from, epochSize)
+	if r.Epochs > 0 {
+		kept := new(big.Int).Mul(new(big.Int).SetUint64(r.Epochs-1), new(big.Int).SetUint64(epochSize))
+		if byEpochs := new(big.Int).Sub(latest, kept); byEpochs.Cmp(cutoff) < 0 {
+			cutoff = byEpochs
+		}
+	}
+	if cutoff.Cmp(latest) > 0 {
+		cutoff = new(big.Int).Set(latest)
+	}
+	if cutoff.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return cutoff
+}
+
+// Prune deletes validators and aggregated public keys of chainID epochs before block. Latest stored epoch is always
+// kept. Returns number of pruned epochs and size of deleted records in bytes
+func (db *ValidatorsStore) Prune(chainID uint8, before *big.Int) (int, int64, error) {
+	latest, err := db.GetLatestKnownEpochLastBlock(chainID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if before.Cmp(latest) > 0 {
+		before = latest
+	}
+	pruned, freed, err := db.storage.DeleteEpochs(chainID, before)
+	if err != nil {
+		return pruned, freed, err
+	}
+	for _, k := range db.apkCache.Keys() {
+		if key := k.(epochKey); key.chainID == chainID && key.block < before.Uint64() {
+			db.apkCache.Remove(key)
+		}
+	}
+	return pruned, freed, nil
+}
+
+// PruneValidators deletes validators history of chainID outside of retention every PruneInterval until stopChn is closed
+func PruneValidators(stopChn <-chan struct{}, db *ValidatorsStore, bs BlockstoreReader, chainID uint8, epochSize uint64, retention *Retention) {
+	for {
+		pruned, freed, err := pruneValidators(db, bs, chainID, epochSize, retention)
+		if err != nil {
+			log.Error().Err(err).Uint8("chain", chainID).Msg("Validators history pruning failed")
+		} else if pruned > 0 {
+			log.Info().Uint8("chain", chainID).Int("epochs", pruned).Int64("freedBytes", freed).Msg("Pruned validators history")
+		}
+		select {
+		case <-stopChn:
+			return
+		case <-time.After(PruneInterval):
+		}
+	}
+}
+
+func pruneValidators(db *ValidatorsStore, bs BlockstoreReader, chainID uint8, epochSize uint64, retention *Retention) (int, int64, error) {
+	checkpoint, err := bs.TryLoadLatestBlock()
+	if err != nil {
+		return 0, 0, err
+	}
+	latest, err := db.GetLatestKnownEpochLastBlock(chainID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return db.Prune(chainID, retention.Cutoff(latest, checkpoint, epochSize))
+}
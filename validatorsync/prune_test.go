@@ -0,0 +1,120 @@
+//Copyright 2020 ChainSafe Systems
+//SPDX-License-Identifier: LGPL-3.0-only
+package validatorsync
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-celo/validatorsync/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PruneTestSuite struct {
+	suite.Suite
+	store      *ValidatorsStore
+	blockstore *mock_validatorsync.MockBlockstoreReader
+}
+
+func TestRunPruneTestSuite(t *testing.T) {
+	suite.Run(t, new(PruneTestSuite))
+}
+func (s *PruneTestSuite) SetupSuite()    {}
+func (s *PruneTestSuite) TearDownSuite() {}
+func (s *PruneTestSuite) SetupTest() {
+	gomockController := gomock.NewController(s.T())
+	s.store = NewValidatorsStoreWithStorage(NewMemoryStorage())
+	s.blockstore = mock_validatorsync.NewMockBlockstoreReader(gomockController)
+}
+func (s *PruneTestSuite) TearDownTest() {
+	s.store.Close()
+}
+
+func (s *PruneTestSuite) storeEpochs(chainID uint8, blocks ...int64) {
+	validators, err := newTestValidators(1, 3)
+	s.Nil(err)
+	for _, b := range blocks {
+		s.Nil(s.store.SetValidatorsForBlock(big.NewInt(b), validatorsData(validators), chainID))
+	}
+}
+
+func (s *PruneTestSuite) epochs(chainID uint8) []int64 {
+	epochs, err := s.store.Epochs(chainID)
+	s.Nil(err)
+	blocks := make([]int64, len(epochs))
+	for i, e := range epochs {
+		blocks[i] = e.Int64()
+	}
+	return blocks
+}
+
+func (s *PruneTestSuite) TestRetentionCutoff() {
+	latest := big.NewInt(120)
+	// Keeps everything when retention is not configured
+	s.Equal(0, (&Retention{}).Cutoff(latest, big.NewInt(100), 12).Cmp(big.NewInt(0)))
+	// Last 3 epochs
+	s.Equal(0, (&Retention{Epochs: 3}).Cutoff(latest, big.NewInt(200), 12).Cmp(big.NewInt(96)))
+	// Listener behind retained epochs still keeps its epoch
+	s.Equal(0, (&Retention{Epochs: 3}).Cutoff(latest, big.NewInt(50), 12).Cmp(big.NewInt(60)))
+	// Blockstore checkpoint minus margin
+	s.Equal(0, (&Retention{Margin: big.NewInt(30)}).Cutoff(latest, big.NewInt(100), 12).Cmp(big.NewInt(72)))
+	s.Equal(0, (&Retention{Margin: big.NewInt(300)}).Cutoff(latest, big.NewInt(100), 12).Cmp(big.NewInt(0)))
+	// Validators sync behind listener keeps latest epoch
+	s.Equal(0, (&Retention{Margin: big.NewInt(0)}).Cutoff(latest, big.NewInt(500), 12).Cmp(latest))
+}
+
+func (s *PruneTestSuite) TestPrune() {
+	s.storeEpochs(1, 0, 12, 24, 36, 48)
+	s.storeEpochs(2, 0, 12, 24)
+	_, err := s.store.GetAPKForBlock(context.Background(), big.NewInt(5), 1, 12)
+	s.Nil(err)
+
+	pruned, freed, err := s.store.Prune(1, big.NewInt(36))
+	s.Nil(err)
+	s.Equal(3, pruned)
+	s.True(freed > 0)
+	s.Equal([]int64{36, 48}, s.epochs(1))
+	s.Equal([]int64{0, 12, 24}, s.epochs(2))
+	_, err = s.store.storage.APK(1, big.NewInt(24))
+	s.True(errors.Is(err, ErrEpochNotFound))
+	_, ok := s.store.apkCache.Get(epochKey{chainID: 1, block: 12})
+	s.False(ok)
+	problems, err := s.store.Verify(1, 12)
+	s.Nil(err)
+	s.Empty(problems)
+
+	// Latest epoch is never pruned
+	pruned, _, err = s.store.Prune(1, big.NewInt(1000))
+	s.Nil(err)
+	s.Equal(1, pruned)
+	s.Equal([]int64{48}, s.epochs(1))
+
+	pruned, freed, err = s.store.Prune(1, big.NewInt(1000))
+	s.Nil(err)
+	s.Equal(0, pruned)
+	s.Equal(int64(0), freed)
+}
+
+func (s *PruneTestSuite) TestPruneValidatorsStopsOnStop() {
+	s.storeEpochs(1, 0, 12, 24, 36, 48)
+	s.blockstore.EXPECT().TryLoadLatestBlock().Return(big.NewInt(40), nil)
+	stopChn := make(chan struct{})
+	close(stopChn)
+
+	done := make(chan struct{})
+	go func() {
+		PruneValidators(stopChn, s.store, s.blockstore, 1, 12, &Retention{Margin: big.NewInt(24)})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		s.Fail("pruning is not stopped")
+	}
+	// Listener at block 40 needs epoch 36, margin keeps epoch 24
+	s.Equal([]int64{24, 36, 48}, s.epochs(1))
+}
@@ -0,0 +1,73 @@
+//Copyright 2020 ChainSafe Systems
+//SPDX-License-Identifier: LGPL-3.0-only
+package validatorsync
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// Validators store key layout. Block numbers are 8 bytes big endian, chainID is a single byte.
+//
+//	validatorsVersion                        -> schema version, 4 bytes big endian
+//	validatorsLatest | chainID               -> latest stored epoch last block
+//	validatorsSet    | chainID | block       -> RLP encoded []*istanbul.ValidatorData after block validators diff
+//	validatorsAPK    | chainID | block       -> serialized aggregated BLS public key of validatorsSet
+const (
+	schemaVersionKey    = "validatorsVersion"
+	latestKeyPrefix     = "validatorsLatest"
+	validatorsKeyPrefix = "validatorsSet"
+	apkKeyPrefix        = "validatorsAPK"
+)
+
+// SchemaVersion is version of validators store layout this relayer reads and writes
+const SchemaVersion = 1
+
+var ErrUnknownSchemaVersion = errors.New("validators store schema is newer than supported")
+
+// Migrate records SchemaVersion in store without version record and refuses store written with newer layout
+func (s *LevelDBStorage) Migrate() error {
+	data, err := s.db.Get([]byte(schemaVersionKey), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return s.db.Put([]byte(schemaVersionKey), encodeVersion(SchemaVersion), nil)
+	}
+	if err != nil {
+		return err
+	}
+	if len(data) != 4 {
+		return fmt.Errorf("malformed validators store version %x", data)
+	}
+	version := binary.BigEndian.Uint32(data)
+	if version > SchemaVersion {
+		return fmt.Errorf("%w: %d, supported %d", ErrUnknownSchemaVersion, version, SchemaVersion)
+	}
+	return nil
+}
+
+func latestKey(chainID uint8) []byte {
+	return append([]byte(latestKeyPrefix), chainID)
+}
+
+func validatorsKey(block *big.Int, chainID uint8) []byte {
+	return append(append([]byte(validatorsKeyPrefix), chainID), encodeBlock(block)...)
+}
+
+func apkKey(block *big.Int, chainID uint8) []byte {
+	return append(append([]byte(apkKeyPrefix), chainID), encodeBlock(block)...)
+}
+
+func encodeBlock(block *big.Int) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, block.Uint64())
+	return b
+}
+
+func encodeVersion(version uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, version)
+	return b
+}
@@ -0,0 +1,56 @@
+//Copyright 2020 ChainSafe Systems
+//SPDX-License-Identifier: LGPL-3.0-only
+package validatorsync
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+type SchemaTestSuite struct {
+	suite.Suite
+	store   *ValidatorsStore
+	storage *LevelDBStorage
+}
+
+func TestRunSchemaTestSuite(t *testing.T) {
+	suite.Run(t, new(SchemaTestSuite))
+}
+func (s *SchemaTestSuite) SetupSuite()    {}
+func (s *SchemaTestSuite) TearDownSuite() {}
+func (s *SchemaTestSuite) SetupTest() {
+	db, err := leveldb.OpenFile("./test/db", nil)
+	if err != nil {
+		s.Fail(err.Error())
+	}
+	s.storage = NewLevelDBStorage(db)
+	s.store = NewValidatorsStoreWithStorage(s.storage)
+}
+func (s *SchemaTestSuite) TearDownTest() {
+	s.store.Close()
+	os.RemoveAll("./test")
+}
+
+func (s *SchemaTestSuite) version() uint32 {
+	data, err := s.storage.db.Get([]byte(schemaVersionKey), nil)
+	s.Nil(err)
+	return binary.BigEndian.Uint32(data)
+}
+
+func (s *SchemaTestSuite) TestMigrateEmptyStore() {
+	s.Nil(s.store.Migrate())
+	s.Equal(uint32(SchemaVersion), s.version())
+	// Versioned store is accepted on next start
+	s.Nil(s.store.Migrate())
+}
+
+func (s *SchemaTestSuite) TestMigrateNewerStoreFails() {
+	s.Nil(s.storage.db.Put([]byte(schemaVersionKey), encodeVersion(SchemaVersion+1), nil))
+	err := s.store.Migrate()
+	s.True(errors.Is(err, ErrUnknownSchemaVersion))
+}
@@ -0,0 +1,245 @@
+//Copyright 2020 ChainSafe Systems
+//SPDX-License-Identifier: LGPL-3.0-only
+package validatorsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var ErrStoreAheadOfSnapshot = errors.New("validators store already contains snapshot epochs")
+var ErrNoEpochSize = errors.New("epoch size should be provided")
+
+// EpochValidators is validators set stored for epoch last block
+type EpochValidators struct {
+	Block      *big.Int                  `json:"block"`
+	Validators []*istanbul.ValidatorData `json:"validators"`
+	APK        hexutil.Bytes             `json:"apk"`
+}
+
+// Snapshot is exported validators history of a chain
+type Snapshot struct {
+	ChainID   uint8              `json:"chainId"`
+	EpochSize uint64             `json:"epochSize"`
+	Epochs    []*EpochValidators `json:"epochs"`
+}
+
+// Chains returns IDs of chains which validators are stored
+func (db *ValidatorsStore) Chains() ([]uint8, error) {
+	return db.storage.Chains()
+}
+
+// Epochs returns stored epoch last blocks of chainID in ascending order
+func (db *ValidatorsStore) Epochs(chainID uint8) ([]*big.Int, error) {
+	return db.storage.Epochs(chainID)
+}
+
+// GetEpoch returns validators stored for epoch last block with their aggregated public key
+func (db *ValidatorsStore) GetEpoch(block *big.Int, chainID uint8) (*EpochValidators, error) {
+	vals, err := db.GetValidatorsForBlock(block, chainID)
+	if err != nil {
+		return nil, err
+	}
+	apk, err := db.storage.APK(chainID, block)
+	if errors.Is(err, ErrEpochNotFound) {
+		apk, err = aggregatePublicKeys(vals)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &EpochValidators{Block: new(big.Int).Set(block), Validators: vals, APK: apk}, nil
+}
+
+// Verify checks that stored epochs of chainID are consecutive epoch last blocks, stored aggregated public keys match
+// validators and latest known block points to last stored epoch. Returns found problems
+func (db *ValidatorsStore) Verify(chainID uint8, epochSize uint64) ([]string, error) {
+	if epochSize == 0 {
+		return nil, ErrNoEpochSize
+	}
+	epochs, err := db.Epochs(chainID)
+	if err != nil {
+		return nil, err
+	}
+	problems := make([]string, 0)
+	if len(epochs) == 0 {
+		return append(problems, "no epochs stored"), nil
+	}
+	for i, block := range epochs {
+		if !istanbul.IsLastBlockOfEpoch(block.Uint64(), epochSize) {
+			problems = append(problems, fmt.Sprintf("block %s is not last block of epoch", block))
+		}
+		if i > 0 {
+			expected := new(big.Int).Add(epochs[i-1], new(big.Int).SetUint64(epochSize))
+			if block.Cmp(expected) != 0 {
+				problems = append(problems, fmt.Sprintf("epochs between %s and %s are missing", epochs[i-1], block))
+			}
+		}
+		vals, err := db.GetValidatorsForBlock(block, chainID)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("validators of block %s can not be read: %s", block, err))
+			continue
+		}
+		stored, err := db.storage.APK(chainID, block)
+		if errors.Is(err, ErrEpochNotFound) {
+			problems = append(problems, fmt.Sprintf("aggregated public key of block %s is missing", block))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		computed, err := aggregatePublicKeys(vals)
+		if err != nil || !bytes.Equal(stored, computed) {
+			problems = append(problems, fmt.Sprintf("aggregated public key of block %s does not match validators", block))
+		}
+	}
+	latest, err := db.GetLatestKnownEpochLastBlock(chainID)
+	if err != nil {
+		return nil, err
+	}
+	if last := epochs[len(epochs)-1]; latest.Cmp(last) != 0 {
+		problems = append(problems, fmt.Sprintf("latest known block %s differs from last stored epoch %s", latest, last))
+	}
+	return problems, nil
+}
+
+// Export returns snapshot of all stored epochs of chainID
+func (db *ValidatorsStore) Export(chainID uint8, epochSize uint64) (*Snapshot, error) {
+	if epochSize == 0 {
+		return nil, ErrNoEpochSize
+	}
+	epochs, err := db.Epochs(chainID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{ChainID: chainID, EpochSize: epochSize, Epochs: make([]*EpochValidators, 0, len(epochs))}
+	for _, block := range epochs {
+		epoch, err := db.GetEpoch(block, chainID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Epochs = append(snapshot.Epochs, epoch)
+	}
+	return snapshot, nil
+}
+
+// Import stores snapshot epochs after checking they are consecutive and their aggregated public keys match validators.
+// Snapshot should continue the latest epoch already known to the store without gaps. Every snapshot epoch is verified
+// against chain: its header should be sealed by validators of the previous epoch and its validators diff should give
+// snapshot validators. Validators of the last epoch are verified by seal of the next epoch header if store is empty
+// and snapshot has a single epoch, so a forged set can not be imported
+func (db *ValidatorsStore) Import(c HeaderByNumberGetter, snapshot *Snapshot) error {
+	if snapshot.EpochSize == 0 {
+		return ErrNoEpochSize
+	}
+	if len(snapshot.Epochs) == 0 {
+		return errors.New("snapshot has no epochs")
+	}
+	for i, epoch := range snapshot.Epochs {
+		if epoch.Block == nil || !istanbul.IsLastBlockOfEpoch(epoch.Block.Uint64(), snapshot.EpochSize) {
+			return fmt.Errorf("snapshot epoch %d is not last block of epoch", i)
+		}
+		if i > 0 {
+			expected := new(big.Int).Add(snapshot.Epochs[i-1].Block, new(big.Int).SetUint64(snapshot.EpochSize))
+			if epoch.Block.Cmp(expected) != 0 {
+				return fmt.Errorf("snapshot epochs between %s and %s are missing", snapshot.Epochs[i-1].Block, epoch.Block)
+			}
+		}
+		if len(epoch.APK) == 0 {
+			continue
+		}
+		apk, err := aggregatePublicKeys(epoch.Validators)
+		if err != nil || !bytes.Equal(apk, epoch.APK) {
+			return fmt.Errorf("aggregated public key of snapshot block %s does not match validators", epoch.Block)
+		}
+	}
+	epochs, err := db.Epochs(snapshot.ChainID)
+	if err != nil {
+		return err
+	}
+	first := snapshot.Epochs[0]
+	if len(epochs) > 0 {
+		latest := epochs[len(epochs)-1]
+		if latest.Cmp(first.Block) >= 0 {
+			return fmt.Errorf("%w: latest stored %s, snapshot starts at %s", ErrStoreAheadOfSnapshot, latest, first.Block)
+		}
+		expected := new(big.Int).Add(latest, new(big.Int).SetUint64(snapshot.EpochSize))
+		if first.Block.Cmp(expected) != 0 {
+			return fmt.Errorf("snapshot should start at epoch %s following latest stored %s, starts at %s", expected, latest, first.Block)
+		}
+		signers, err := db.GetValidatorsForBlock(latest, snapshot.ChainID)
+		if err != nil {
+			return err
+		}
+		err = verifyEpoch(c, first, signers)
+		if err != nil {
+			return err
+		}
+	} else if len(snapshot.Epochs) == 1 {
+		next := new(big.Int).Add(first.Block, new(big.Int).SetUint64(snapshot.EpochSize))
+		_, err = verifyEpochHeader(c, next, first.Validators)
+		if err != nil {
+			return err
+		}
+	}
+	for i := 1; i < len(snapshot.Epochs); i++ {
+		err = verifyEpoch(c, snapshot.Epochs[i], snapshot.Epochs[i-1].Validators)
+		if err != nil {
+			return err
+		}
+	}
+	for _, epoch := range snapshot.Epochs {
+		err = db.SetValidatorsForBlock(epoch.Block, epoch.Validators, snapshot.ChainID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyEpoch checks that epoch header is sealed by signers and its validators diff gives epoch validators
+func verifyEpoch(c HeaderByNumberGetter, epoch *EpochValidators, signers []*istanbul.ValidatorData) error {
+	validators, err := verifyEpochHeader(c, epoch.Block, signers)
+	if err != nil {
+		return err
+	}
+	if !sameValidators(validators, epoch.Validators) {
+		return fmt.Errorf("validators of snapshot block %s do not match chain header", epoch.Block)
+	}
+	return nil
+}
+
+// verifyEpochHeader checks that epoch header of block is sealed by signers and returns validators resulting from its diff
+func verifyEpochHeader(c HeaderByNumberGetter, block *big.Int, signers []*istanbul.ValidatorData) ([]*istanbul.ValidatorData, error) {
+	header, err := c.HeaderByNumber(context.Background(), block)
+	if err != nil {
+		return nil, fmt.Errorf("gettings epoch header %s err: %w", block, err)
+	}
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return nil, fmt.Errorf("error on extracting istanbul extra: %w", err)
+	}
+	err = verifyAggregatedSeal(header, extra.AggregatedSeal, signers)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot validators did not sign block %s: %w", block, err)
+	}
+	return applyValidatorsDiff(extra, signers)
+}
+
+func sameValidators(a, b []*istanbul.ValidatorData) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Address != b[i].Address || a[i].BLSPublicKey != b[i].BLSPublicKey {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,208 @@
+//Copyright 2020 ChainSafe Systems
+//SPDX-License-Identifier: LGPL-3.0-only
+package validatorsync
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	mock_validatorsync "github.com/ChainSafe/chainbridge-celo/validatorsync/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SnapshotTestSuite struct {
+	suite.Suite
+	store   *ValidatorsStore
+	storage *MemoryStorage
+	client  *mock_validatorsync.MockHeaderByNumberGetter
+}
+
+func TestRunSnapshotTestSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotTestSuite))
+}
+func (s *SnapshotTestSuite) SetupSuite()    {}
+func (s *SnapshotTestSuite) TearDownSuite() {}
+func (s *SnapshotTestSuite) SetupTest() {
+	gomockController := gomock.NewController(s.T())
+	s.client = mock_validatorsync.NewMockHeaderByNumberGetter(gomockController)
+	s.storage = NewMemoryStorage()
+	s.store = NewValidatorsStoreWithStorage(s.storage)
+}
+func (s *SnapshotTestSuite) TearDownTest() {
+	s.store.Close()
+}
+
+func (s *SnapshotTestSuite) storeEpochs(chainID uint8, blocks ...int64) {
+	validators, err := newTestValidators(1, 3)
+	s.Nil(err)
+	for _, b := range blocks {
+		s.Nil(s.store.SetValidatorsForBlock(big.NewInt(b), validatorsData(validators), chainID))
+	}
+}
+
+func (s *SnapshotTestSuite) TestChainsAndEpochs() {
+	s.storeEpochs(1, 0, 12, 24)
+	s.storeEpochs(2, 36)
+
+	chains, err := s.store.Chains()
+	s.Nil(err)
+	s.Equal([]uint8{1, 2}, chains)
+	epochs, err := s.store.Epochs(1)
+	s.Nil(err)
+	s.Len(epochs, 3)
+	for i, expected := range []int64{0, 12, 24} {
+		s.Equal(0, epochs[i].Cmp(big.NewInt(expected)))
+	}
+}
+
+func (s *SnapshotTestSuite) TestVerify() {
+	s.storeEpochs(1, 0, 12, 24)
+	problems, err := s.store.Verify(1, 12)
+	s.Nil(err)
+	s.Empty(problems)
+
+	// Gap, missing key and block which is not epoch last
+	s.storeEpochs(1, 48, 50)
+	delete(s.storage.chains[1].apks, 12)
+	problems, err = s.store.Verify(1, 12)
+	s.Nil(err)
+	s.Len(problems, 4)
+
+	_, err = s.store.Verify(1, 0)
+	s.True(errors.Is(err, ErrNoEpochSize))
+}
+
+func (s *SnapshotTestSuite) TestExportImport() {
+	s.storeEpochs(1, 0, 12, 24)
+	snapshot, err := s.store.Export(1, 12)
+	s.Nil(err)
+	s.Len(snapshot.Epochs, 3)
+	data, err := json.Marshal(snapshot)
+	s.Nil(err)
+
+	// Snapshot is imported into another relayer store, every epoch header is sealed by previous snapshot validators
+	s.SetupTest()
+	imported := &Snapshot{}
+	s.Nil(json.Unmarshal(data, imported))
+	signers, err := newTestValidators(1, 3)
+	s.Nil(err)
+	for _, block := range []int64{12, 24} {
+		header, err := generateSignedHeader(uint64(block), nil, signers, big.NewInt(7))
+		s.Nil(err)
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(block)).Return(header, nil)
+	}
+	s.Nil(s.store.Import(s.client, imported))
+
+	problems, err := s.store.Verify(1, 12)
+	s.Nil(err)
+	s.Empty(problems)
+	latest, err := s.store.GetLatestKnownEpochLastBlock(1)
+	s.Nil(err)
+	s.Equal(0, latest.Cmp(big.NewInt(24)))
+
+	// Store already contains snapshot epochs
+	err = s.store.Import(s.client, imported)
+	s.True(errors.Is(err, ErrStoreAheadOfSnapshot))
+}
+
+func (s *SnapshotTestSuite) TestImportRejectsInvalidSnapshot() {
+	s.storeEpochs(1, 0, 12, 24)
+	snapshot, err := s.store.Export(1, 12)
+	s.Nil(err)
+	s.SetupTest()
+
+	snapshot.Epochs[1].APK = snapshot.Epochs[1].APK[1:]
+	s.NotNil(s.store.Import(s.client, snapshot))
+
+	snapshot.Epochs = append(snapshot.Epochs[:1], snapshot.Epochs[2:]...)
+	s.NotNil(s.store.Import(s.client, snapshot))
+
+	epochs, err := s.store.Epochs(1)
+	s.Nil(err)
+	s.Empty(epochs)
+}
+
+func (s *SnapshotTestSuite) TestImportContinuesStore() {
+	s.storeEpochs(1, 0, 12)
+	signers, err := newTestValidators(1, 3)
+	s.Nil(err)
+	added, err := newTestValidators(4, 1)
+	s.Nil(err)
+	header, err := generateSignedHeader(24, added, signers, big.NewInt(7))
+	s.Nil(err)
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(24)).Return(header, nil)
+
+	snapshot := &Snapshot{ChainID: 1, EpochSize: 12, Epochs: []*EpochValidators{
+		{Block: big.NewInt(24), Validators: append(validatorsData(signers), added[0].data)},
+	}}
+	s.Nil(s.store.Import(s.client, snapshot))
+	latest, err := s.store.GetLatestKnownEpochLastBlock(1)
+	s.Nil(err)
+	s.Equal(0, latest.Cmp(big.NewInt(24)))
+}
+
+func (s *SnapshotTestSuite) TestImportRejectsGap() {
+	s.storeEpochs(1, 0)
+	validators, err := newTestValidators(1, 3)
+	s.Nil(err)
+	snapshot := &Snapshot{ChainID: 1, EpochSize: 12, Epochs: []*EpochValidators{
+		{Block: big.NewInt(24), Validators: validatorsData(validators)},
+	}}
+	s.NotNil(s.store.Import(s.client, snapshot))
+	epochs, err := s.store.Epochs(1)
+	s.Nil(err)
+	s.Len(epochs, 1)
+}
+
+func (s *SnapshotTestSuite) TestImportRejectsForgedValidators() {
+	signers, err := newTestValidators(1, 3)
+	s.Nil(err)
+	forged, err := newTestValidators(5, 3)
+	s.Nil(err)
+	snapshot := &Snapshot{ChainID: 1, EpochSize: 12, Epochs: []*EpochValidators{
+		{Block: big.NewInt(24), Validators: validatorsData(forged)},
+	}}
+
+	// Into empty store next epoch header is not sealed by forged set
+	header, err := generateSignedHeader(36, nil, signers, big.NewInt(7))
+	s.Nil(err)
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(36)).Return(header, nil)
+	s.NotNil(s.store.Import(s.client, snapshot))
+	epochs, err := s.store.Epochs(1)
+	s.Nil(err)
+	s.Empty(epochs)
+
+	// Into non-empty store diff of first epoch header does not give forged set
+	s.storeEpochs(1, 12)
+	header, err = generateSignedHeader(24, nil, signers, big.NewInt(7))
+	s.Nil(err)
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(24)).Return(header, nil)
+	s.NotNil(s.store.Import(s.client, snapshot))
+	epochs, err = s.store.Epochs(1)
+	s.Nil(err)
+	s.Len(epochs, 1)
+}
+
+func (s *SnapshotTestSuite) TestImportRejectsForgedLaterEpoch() {
+	signers, err := newTestValidators(1, 3)
+	s.Nil(err)
+	forged, err := newTestValidators(5, 3)
+	s.Nil(err)
+	snapshot := &Snapshot{ChainID: 1, EpochSize: 12, Epochs: []*EpochValidators{
+		{Block: big.NewInt(12), Validators: validatorsData(signers)},
+		{Block: big.NewInt(24), Validators: validatorsData(signers)},
+		{Block: big.NewInt(36), Validators: validatorsData(forged)},
+	}}
+	for _, block := range []int64{24, 36} {
+		header, err := generateSignedHeader(uint64(block), nil, signers, big.NewInt(7))
+		s.Nil(err)
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(block)).Return(header, nil)
+	}
+	s.NotNil(s.store.Import(s.client, snapshot))
+	epochs, err := s.store.Epochs(1)
+	s.Nil(err)
+	s.Empty(epochs)
+}
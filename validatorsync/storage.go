@@ -0,0 +1,38 @@
+//Copyright 2020 ChainSafe Systems
+//SPDX-License-Identifier: LGPL-3.0-only
+package validatorsync
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+)
+
+var ErrEpochNotFound = errors.New("epoch is not stored")
+
+// ValidatorsStorage persists validators of epoch last blocks and their aggregated public keys for ValidatorsStore.
+// Validators and aggregated public key getters return ErrEpochNotFound for epochs which are not stored
+type ValidatorsStorage interface {
+	// LatestEpoch returns latest stored epoch last block of chainID, 0 if nothing is stored
+	LatestEpoch(chainID uint8) (*big.Int, error)
+	// PutEpoch atomically stores validators of epoch last block, their aggregated public key unless it is nil
+	// and sets block as latest epoch of chainID
+	PutEpoch(chainID uint8, block *big.Int, validators []*istanbul.ValidatorData, apk []byte) error
+	Validators(chainID uint8, block *big.Int) ([]*istanbul.ValidatorData, error)
+	APK(chainID uint8, block *big.Int) ([]byte, error)
+	PutAPK(chainID uint8, block *big.Int, apk []byte) error
+	// Chains returns IDs of chains with latest epoch set in ascending order
+	Chains() ([]uint8, error)
+	// Epochs returns stored epoch last blocks of chainID in ascending order
+	Epochs(chainID uint8) ([]*big.Int, error)
+	// DeleteEpochs deletes validators and aggregated public keys of chainID epochs before block. Returns number of
+	// deleted epochs and size of deleted records in bytes
+	DeleteEpochs(chainID uint8, before *big.Int) (int, int64, error)
+	Close() error
+}
+
+// migrator is implemented by storages with versioned layout
+type migrator interface {
+	Migrate() error
+}
@@ -0,0 +1,152 @@
+//Copyright 2020 ChainSafe Systems
+//SPDX-License-Identifier: LGPL-3.0-only
+package validatorsync
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// StorageTestSuite is conformance suite every ValidatorsStorage implementation should pass
+type StorageTestSuite struct {
+	suite.Suite
+	newStorage func() (ValidatorsStorage, error)
+	storage    ValidatorsStorage
+}
+
+func TestRunLevelDBStorageTestSuite(t *testing.T) {
+	suite.Run(t, &StorageTestSuite{newStorage: func() (ValidatorsStorage, error) {
+		db, err := leveldb.OpenFile("./test/db", nil)
+		if err != nil {
+			return nil, err
+		}
+		return NewLevelDBStorage(db), nil
+	}})
+}
+
+func TestRunMemoryStorageTestSuite(t *testing.T) {
+	suite.Run(t, &StorageTestSuite{newStorage: func() (ValidatorsStorage, error) {
+		return NewMemoryStorage(), nil
+	}})
+}
+
+func (s *StorageTestSuite) SetupSuite()    {}
+func (s *StorageTestSuite) TearDownSuite() {}
+func (s *StorageTestSuite) SetupTest() {
+	storage, err := s.newStorage()
+	if err != nil {
+		s.Fail(err.Error())
+	}
+	s.storage = storage
+}
+func (s *StorageTestSuite) TearDownTest() {
+	s.storage.Close()
+	os.RemoveAll("./test")
+}
+
+func (s *StorageTestSuite) TestEmptyStorage() {
+	latest, err := s.storage.LatestEpoch(1)
+	s.Nil(err)
+	s.Equal(0, latest.Cmp(big.NewInt(0)))
+	_, err = s.storage.Validators(1, big.NewInt(0))
+	s.True(errors.Is(err, ErrEpochNotFound))
+	_, err = s.storage.APK(1, big.NewInt(0))
+	s.True(errors.Is(err, ErrEpochNotFound))
+	chains, err := s.storage.Chains()
+	s.Nil(err)
+	s.Empty(chains)
+	epochs, err := s.storage.Epochs(1)
+	s.Nil(err)
+	s.Empty(epochs)
+}
+
+func (s *StorageTestSuite) TestPutEpoch() {
+	validators, err := newTestValidators(1, 3)
+	s.Nil(err)
+	s.Nil(s.storage.PutEpoch(1, big.NewInt(12), validatorsData(validators), []byte{0x01, 0x02}))
+
+	vals, err := s.storage.Validators(1, big.NewInt(12))
+	s.Nil(err)
+	s.Equal(validatorsData(validators), vals)
+	apk, err := s.storage.APK(1, big.NewInt(12))
+	s.Nil(err)
+	s.Equal([]byte{0x01, 0x02}, apk)
+	latest, err := s.storage.LatestEpoch(1)
+	s.Nil(err)
+	s.Equal(0, latest.Cmp(big.NewInt(12)))
+
+	// Other chain is not affected
+	latest, err = s.storage.LatestEpoch(2)
+	s.Nil(err)
+	s.Equal(0, latest.Cmp(big.NewInt(0)))
+	_, err = s.storage.Validators(2, big.NewInt(12))
+	s.True(errors.Is(err, ErrEpochNotFound))
+}
+
+func (s *StorageTestSuite) TestPutEpochWithoutAPK() {
+	validators, err := newTestValidators(1, 3)
+	s.Nil(err)
+	s.Nil(s.storage.PutEpoch(1, big.NewInt(12), validatorsData(validators), nil))
+	_, err = s.storage.APK(1, big.NewInt(12))
+	s.True(errors.Is(err, ErrEpochNotFound))
+
+	s.Nil(s.storage.PutAPK(1, big.NewInt(12), []byte{0x03}))
+	apk, err := s.storage.APK(1, big.NewInt(12))
+	s.Nil(err)
+	s.Equal([]byte{0x03}, apk)
+}
+
+func (s *StorageTestSuite) TestChainsAndEpochs() {
+	validators, err := newTestValidators(1, 3)
+	s.Nil(err)
+	for _, b := range []int64{24, 0, 12, 256} {
+		s.Nil(s.storage.PutEpoch(2, big.NewInt(b), validatorsData(validators), nil))
+	}
+	s.Nil(s.storage.PutEpoch(1, big.NewInt(12), validatorsData(validators), nil))
+
+	chains, err := s.storage.Chains()
+	s.Nil(err)
+	s.Equal([]uint8{1, 2}, chains)
+	epochs, err := s.storage.Epochs(2)
+	s.Nil(err)
+	s.Len(epochs, 4)
+	for i, expected := range []int64{0, 12, 24, 256} {
+		s.Equal(0, epochs[i].Cmp(big.NewInt(expected)))
+	}
+	// Latest epoch is the last one stored
+	latest, err := s.storage.LatestEpoch(2)
+	s.Nil(err)
+	s.Equal(0, latest.Cmp(big.NewInt(256)))
+}
+
+func (s *StorageTestSuite) TestDeleteEpochs() {
+	validators, err := newTestValidators(1, 3)
+	s.Nil(err)
+	for _, b := range []int64{0, 12, 24, 36} {
+		s.Nil(s.storage.PutEpoch(1, big.NewInt(b), validatorsData(validators), []byte{0x01}))
+	}
+	s.Nil(s.storage.PutEpoch(2, big.NewInt(12), validatorsData(validators), []byte{0x01}))
+
+	deleted, freed, err := s.storage.DeleteEpochs(1, big.NewInt(24))
+	s.Nil(err)
+	s.Equal(2, deleted)
+	s.True(freed > 0)
+	epochs, err := s.storage.Epochs(1)
+	s.Nil(err)
+	s.Len(epochs, 2)
+	s.Equal(0, epochs[0].Cmp(big.NewInt(24)))
+	_, err = s.storage.APK(1, big.NewInt(12))
+	s.True(errors.Is(err, ErrEpochNotFound))
+	_, err = s.storage.Validators(2, big.NewInt(12))
+	s.Nil(err)
+
+	deleted, freed, err = s.storage.DeleteEpochs(1, big.NewInt(24))
+	s.Nil(err)
+	s.Equal(0, deleted)
+	s.Equal(int64(0), freed)
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/consensus/istanbul"
@@ -25,7 +26,12 @@ type HeaderByNumberGetter interface {
 	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
 }
 
-func SyncBlockValidators(stopChn <-chan struct{}, errChn chan error, c HeaderByNumberGetter, db *ValidatorsStore, chainID uint8, epochSize uint64) {
+// SyncBlockValidators stores validators of every epoch starting from latest known one. Up to concurrency epoch headers are fetched in parallel
+// while they are verified and stored in order
+func SyncBlockValidators(stopChn <-chan struct{}, errChn chan error, c HeaderByNumberGetter, db *ValidatorsStore, chainID uint8, epochSize uint64, concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
 	var prevValidators []*istanbul.ValidatorData
 	// If DB is empty will return 0 (first epoch by itself)
 	block, err := db.GetLatestKnownEpochLastBlock(chainID)
@@ -47,43 +53,97 @@ func SyncBlockValidators(stopChn <-chan struct{}, errChn chan error, c HeaderByN
 		block.Add(block, big.NewInt(0).SetUint64(epochSize))
 		log.Info().Msgf("Syncing validators from %s block", block.String())
 	}
+	// Headers are fetched ahead while validators tip is far, near the tip only next epoch header is requested
+	atTip := false
+	requests := db.epochRequests(chainID)
 	for {
 		select {
 		case <-stopChn:
 			return
 		default:
-			header, err := c.HeaderByNumber(context.Background(), block)
-			if err != nil {
-				if errors.Is(err, ethereum.NotFound) {
-					// Block not yet mined, waiting
-					time.Sleep(timeToWaitUntilNextBlockAppear * time.Second)
-					continue
+			count := concurrency
+			if atTip {
+				count = 1
+			}
+			headers, fetchErr := fetchEpochHeaders(c, block, epochSize, count)
+			for _, header := range headers {
+				prevValidators, err = processEpochHeader(db, header, block, prevValidators, chainID)
+				if err != nil {
+					errChn <- err
+					return
 				}
-				errChn <- fmt.Errorf("gettings header by number err: %w", err)
-				return
+				// Current validators for next epoch, will be set for next last epoch block and applied with its diff
+				block.Add(block, big.NewInt(0).SetUint64(epochSize))
 			}
-			extra, err := types.ExtractIstanbulExtra(header)
-			if err != nil {
-				errChn <- fmt.Errorf("error on extracting istanbul extra: %w", err)
+			if fetchErr != nil {
+				errChn <- fmt.Errorf("gettings header by number err: %w", fetchErr)
 				return
 			}
-			b := bytes.NewBuffer(extra.RemovedValidators.Bytes())
-
-			if len(extra.AddedValidators) != 0 || b.Len() > 0 {
-				log.Debug().Str("block", block.String()).Msg("New validators data")
-				prevValidators, err = applyValidatorsDiff(extra, prevValidators)
-				if err != nil {
-					errChn <- fmt.Errorf("error applying validators diff: %w", err)
+			atTip = len(headers) < count
+			if atTip {
+				// Block not yet mined, waiting until it appears or is requested by validators consumer
+				select {
+				case <-stopChn:
 					return
+				case <-time.After(timeToWaitUntilNextBlockAppear * time.Second):
+				case <-requests:
 				}
 			}
-			err = db.SetValidatorsForBlock(block, prevValidators, chainID)
-			if err != nil {
-				errChn <- fmt.Errorf("error on set validators to db: %w", err)
-				return
+		}
+	}
+}
+
+// fetchEpochHeaders concurrently fetches count epoch last block headers starting from block.
+// Returned headers are ordered and end before first header that is not mined yet or failed to be fetched
+func fetchEpochHeaders(c HeaderByNumberGetter, block *big.Int, epochSize uint64, count int) ([]*types.Header, error) {
+	headers := make([]*types.Header, count)
+	errs := make([]error, count)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int, number *big.Int) {
+			defer wg.Done()
+			headers[i], errs[i] = c.HeaderByNumber(context.Background(), number)
+		}(i, new(big.Int).Add(block, new(big.Int).SetUint64(epochSize*uint64(i))))
+	}
+	wg.Wait()
+	for i := range headers {
+		if errs[i] != nil {
+			if errors.Is(errs[i], ethereum.NotFound) {
+				return headers[:i], nil
 			}
-			// Current validators for next epoch, will be set for next last epoch block and applied with its diff
-			block.Add(block, big.NewInt(0).SetUint64(epochSize))
+			return headers[:i], errs[i]
 		}
 	}
+	return headers, nil
+}
+
+// processEpochHeader verifies epoch last block header, applies its validators diff and stores resulting validators
+func processEpochHeader(db *ValidatorsStore, header *types.Header, block *big.Int, prevValidators []*istanbul.ValidatorData, chainID uint8) ([]*istanbul.ValidatorData, error) {
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return nil, fmt.Errorf("error on extracting istanbul extra: %w", err)
+	}
+	// Genesis block is not sealed, every next epoch header should be signed by quorum of previous validators
+	if block.Sign() > 0 {
+		err = verifyAggregatedSeal(header, extra.AggregatedSeal, prevValidators)
+		if err != nil {
+			return nil, fmt.Errorf("epoch header %s verification failed: %w", block.String(), err)
+		}
+	}
+	b := bytes.NewBuffer(extra.RemovedValidators.Bytes())
+
+	validators := prevValidators
+	if len(extra.AddedValidators) != 0 || b.Len() > 0 {
+		log.Debug().Str("block", block.String()).Msg("New validators data")
+		validators, err = applyValidatorsDiff(extra, prevValidators)
+		if err != nil {
+			return nil, fmt.Errorf("error applying validators diff: %w", err)
+		}
+	}
+	err = db.SetValidatorsForBlock(block, validators, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("error on set validators to db: %w", err)
+	}
+	return validators, nil
 }
@@ -3,22 +3,17 @@
 package validatorsync
 
 import (
+	"context"
 	"errors"
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/crypto/bls"
-	"github.com/ethereum/go-ethereum/rlp"
 	"math/big"
-	"os"
 	"testing"
 	"time"
 
 	"github.com/ChainSafe/chainbridge-celo/validatorsync/mock"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/suite"
-	"github.com/syndtr/goleveldb/leveldb"
 )
 
 type SyncTestSuite struct {
@@ -34,77 +29,38 @@ func (s *SyncTestSuite) SetupSuite()    {}
 func (s *SyncTestSuite) TearDownSuite() {}
 func (s *SyncTestSuite) SetupTest() {
 	gomockController := gomock.NewController(s.T())
-	db, err := leveldb.OpenFile("./test/db", nil)
-	if err != nil {
-		s.Fail(err.Error())
-	}
-	syncer := NewValidatorsStore(db)
-	s.store = syncer
+	s.store = NewValidatorsStoreWithStorage(NewMemoryStorage())
 	s.client = mock_validatorsync.NewMockHeaderByNumberGetter(gomockController)
-
 }
 func (s *SyncTestSuite) TearDownTest() {
 	s.store.Close()
-	os.RemoveAll("./test")
-}
-
-func generateBlockHeader() (*types.Header, error) {
-	testKey2, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f292")
-	testKey3, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f293")
-	blsPK2, _ := blscrypto.ECDSAToBLS(testKey2)
-	blsPK3, _ := blscrypto.ECDSAToBLS(testKey3)
-	pubKey2, _ := blscrypto.PrivateToPublic(blsPK2)
-	pubKey3, _ := blscrypto.PrivateToPublic(blsPK3)
-	extra, err := rlp.EncodeToBytes(&types.IstanbulExtra{
-		AddedValidators: []common.Address{
-			common.BytesToAddress(hexutil.MustDecode("0x44add0ec310f115a0e603b2d7db9f067778eaf8a")),
-			common.BytesToAddress(hexutil.MustDecode("0x294fc7e8f22b3bcdcf955dd7ff3ba2ed833f8212")),
-		},
-		AddedValidatorsPublicKeys: []blscrypto.SerializedPublicKey{
-			pubKey2,
-			pubKey3,
-		},
-		RemovedValidators:    big.NewInt(0),
-		Seal:                 []byte{},
-		AggregatedSeal:       types.IstanbulAggregatedSeal{},
-		ParentAggregatedSeal: types.IstanbulAggregatedSeal{},
-	})
-	if err != nil {
-		return nil, err
-	}
-	h := &types.Header{
-		Extra: append(make([]byte, types.IstanbulExtraVanity), extra...),
-	}
-	return h, nil
 }
 
 func (s *SyncTestSuite) TestStoreBlockValidatorsWIthEmptyDB() {
-	header, err := generateBlockHeader()
+	validators, err := newTestValidators(1, 6)
+	s.Nil(err)
+	// Genesis adds first two validators, every next epoch is signed by quorum of previous set and adds two more
+	genesis, err := generateSignedHeader(0, validators[0:2], nil, nil)
+	s.Nil(err)
+	header12, err := generateSignedHeader(12, validators[2:4], validators[0:2], big.NewInt(3))
+	s.Nil(err)
+	header24, err := generateSignedHeader(24, validators[4:6], validators[0:4], big.NewInt(7))
 	s.Nil(err)
 	stopChn := make(chan struct{})
-	errChn := make(chan error)
+	errChn := make(chan error, 1)
 	chainID := uint8(1)
-	// First iteration
-	s.client.EXPECT().HeaderByNumber(gomock.Any(), gomock.Any()).Return(header, nil)
-	//Second iteration
-	s.client.EXPECT().HeaderByNumber(gomock.Any(), gomock.Any()).Return(header, nil)
-	//Third iteration
-	s.client.EXPECT().HeaderByNumber(gomock.Any(), gomock.Any()).Return(header, nil)
-	// Erroring to stop routine
 	e := errors.New("some error occured")
-	s.client.EXPECT().HeaderByNumber(gomock.Any(), gomock.Any()).Return(nil, e)
+	gomock.InOrder(
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(0)).Return(genesis, nil),
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(12)).Return(header12, nil),
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(24)).Return(header24, nil),
+		// Erroring to stop routine
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(36)).Return(nil, e),
+	)
 
-	go func() {
-		select {
-		case err := <-errChn:
-			s.True(errors.Is(err, e))
-		case <-time.After(time.Second * 10):
-			// Closing this goroutine after 10 seconds
-			return
-		}
-	}()
+	SyncBlockValidators(stopChn, errChn, s.client, s.store, chainID, 12, 1)
 
-	SyncBlockValidators(stopChn, errChn, s.client, s.store, chainID, 12)
+	s.True(errors.Is(<-errChn, e))
 
 	vals, err := s.store.GetValidatorsForBlock(big.NewInt(0), chainID)
 	s.Nil(err)
@@ -125,8 +81,133 @@ func (s *SyncTestSuite) TestStoreBlockValidatorsWIthEmptyDB() {
 	s.Nil(err)
 	s.Equal(0, lb.Cmp(big.NewInt(24)))
 
-	apk, err := s.store.GetAPKForBlock(big.NewInt(1), chainID, 12)
+	apk, err := s.store.GetAPKForBlock(context.Background(), big.NewInt(1), chainID, 12)
 	s.Nil(err)
 	s.NotNil(apk)
 
 }
+
+func (s *SyncTestSuite) TestSyncStopsOnForgedEpochHeader() {
+	validators, err := newTestValidators(1, 4)
+	s.Nil(err)
+	forgers, err := newTestValidators(5, 2)
+	s.Nil(err)
+	genesis, err := generateSignedHeader(0, validators, nil, nil)
+	s.Nil(err)
+	// Header adds validators and is signed by keys that are not in the current set
+	forged, err := generateSignedHeader(12, forgers, forgers, big.NewInt(3))
+	s.Nil(err)
+	errChn := make(chan error, 1)
+	chainID := uint8(1)
+	gomock.InOrder(
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(0)).Return(genesis, nil),
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(12)).Return(forged, nil),
+	)
+
+	SyncBlockValidators(make(chan struct{}), errChn, s.client, s.store, chainID, 12, 1)
+
+	err = <-errChn
+	s.True(errors.Is(err, ErrInsufficientSeals))
+	lb, err := s.store.GetLatestKnownEpochLastBlock(chainID)
+	s.Nil(err)
+	s.Equal(0, lb.Cmp(big.NewInt(0)))
+	_, err = s.store.GetValidatorsForBlock(big.NewInt(12), chainID)
+	s.NotNil(err)
+}
+
+func (s *SyncTestSuite) TestSyncStopsOnInvalidSignature() {
+	validators, err := newTestValidators(1, 3)
+	s.Nil(err)
+	genesis, err := generateSignedHeader(0, validators, nil, nil)
+	s.Nil(err)
+	header, err := generateSignedHeader(12, nil, validators, big.NewInt(7))
+	s.Nil(err)
+	// Swapping signer keys keeps bitmap quorum but breaks aggregated signature
+	other, err := newTestValidators(4, 1)
+	s.Nil(err)
+	signers := []*testValidator{validators[0], validators[1], other[0]}
+	badHeader, err := generateSignedHeader(12, nil, signers, big.NewInt(7))
+	s.Nil(err)
+	s.NotEqual(header.Extra, badHeader.Extra)
+	errChn := make(chan error, 1)
+	chainID := uint8(1)
+	gomock.InOrder(
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(0)).Return(genesis, nil),
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(12)).Return(badHeader, nil),
+	)
+
+	SyncBlockValidators(make(chan struct{}), errChn, s.client, s.store, chainID, 12, 1)
+
+	err = <-errChn
+	s.True(errors.Is(err, ErrInvalidSignature))
+}
+
+func (s *SyncTestSuite) TestSyncFetchesHeadersConcurrently() {
+	validators, err := newTestValidators(1, 6)
+	s.Nil(err)
+	genesis, err := generateSignedHeader(0, validators[0:2], nil, nil)
+	s.Nil(err)
+	header12, err := generateSignedHeader(12, validators[2:4], validators[0:2], big.NewInt(3))
+	s.Nil(err)
+	header24, err := generateSignedHeader(24, validators[4:6], validators[0:4], big.NewInt(7))
+	s.Nil(err)
+	errChn := make(chan error, 1)
+	chainID := uint8(1)
+	e := errors.New("some error occured")
+	// All headers of the window are requested at once, order of requests is not defined
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(0)).Return(genesis, nil)
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(12)).Return(header12, nil)
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(24)).Return(header24, nil)
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(36)).Return(nil, e)
+
+	SyncBlockValidators(make(chan struct{}), errChn, s.client, s.store, chainID, 12, 4)
+
+	err = <-errChn
+	s.True(errors.Is(err, e))
+	// Headers fetched before failed one are still stored in order
+	vals, err := s.store.GetValidatorsForBlock(big.NewInt(24), chainID)
+	s.Nil(err)
+	s.Equal(validatorsData(validators), vals)
+	lb, err := s.store.GetLatestKnownEpochLastBlock(chainID)
+	s.Nil(err)
+	s.Equal(0, lb.Cmp(big.NewInt(24)))
+}
+
+func (s *SyncTestSuite) TestFetchEpochHeadersStopsAtNotMinedHeader() {
+	header, err := generateSignedHeader(12, nil, nil, nil)
+	s.Nil(err)
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(12)).Return(header, nil)
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(24)).Return(nil, ethereum.NotFound)
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(36)).Return(nil, ethereum.NotFound)
+
+	headers, err := fetchEpochHeaders(s.client, big.NewInt(12), 12, 3)
+	s.Nil(err)
+	s.Equal([]*types.Header{header}, headers)
+}
+
+func (s *SyncTestSuite) TestSyncWakesUpOnEpochRequest() {
+	validators, err := newTestValidators(1, 2)
+	s.Nil(err)
+	genesis, err := generateSignedHeader(0, validators, nil, nil)
+	s.Nil(err)
+	errChn := make(chan error, 1)
+	chainID := uint8(1)
+	e := errors.New("some error occured")
+	gomock.InOrder(
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(0)).Return(genesis, nil),
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(12)).Return(nil, ethereum.NotFound),
+		s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(12)).Return(nil, e),
+	)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, _ = s.store.WaitForEpoch(ctx, big.NewInt(12), chainID)
+	}()
+
+	start := time.Now()
+	SyncBlockValidators(make(chan struct{}), errChn, s.client, s.store, chainID, 12, 1)
+
+	s.True(errors.Is(<-errChn, e))
+	// Header is requested again without waiting for next block timeout
+	s.True(time.Since(start) < timeToWaitUntilNextBlockAppear*time.Second)
+}
@@ -3,147 +3,246 @@
 package validatorsync
 
 import (
-	"bytes"
-	"encoding/binary"
-	"encoding/gob"
+	"context"
 	"errors"
+	"fmt"
 	"math/big"
-	"time"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rs/zerolog/log"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
-const (
-	latestKnownBlockKey = "latestKnownBlock"
-)
+// Number of epochs aggregated public keys are kept in memory for
+const apkCacheSize = 128
 
+// NewValidatorsStore creates store of validators kept in LevelDB database
 func NewValidatorsStore(db *leveldb.DB) *ValidatorsStore {
-	return &ValidatorsStore{db: db}
+	return NewValidatorsStoreWithStorage(NewLevelDBStorage(db))
+}
+
+func NewValidatorsStoreWithStorage(storage ValidatorsStorage) *ValidatorsStore {
+	// Error is returned only for non-positive size
+	apkCache, _ := lru.New(apkCacheSize)
+	return &ValidatorsStore{
+		storage:  storage,
+		apkCache: apkCache,
+		waiters:  make(map[epochKey][]chan struct{}),
+		requests: make(map[uint8]chan struct{}),
+	}
+}
+
+type epochKey struct {
+	chainID uint8
+	block   uint64 // epoch last block
 }
 
 type ValidatorsStore struct {
-	db *leveldb.DB
+	storage  ValidatorsStorage
+	apkCache *lru.Cache                   // serialized aggregated public keys by epochKey
+	waiters  map[epochKey][]chan struct{} // closed when validators of epoch are stored
+	requests map[uint8]chan struct{}      // signals validators sync that epoch is awaited
+	lock     sync.Mutex
+}
+
+// Migrate upgrades storage with versioned layout to its current version
+func (db *ValidatorsStore) Migrate() error {
+	if m, ok := db.storage.(migrator); ok {
+		return m.Migrate()
+	}
+	return nil
 }
 
 // GetLatestKnownBlock returns block number of latest parsed EpochLastBlock for provided chainID. If DB is empty returns 0.
 // Should always be last block in epoch.
 func (db *ValidatorsStore) GetLatestKnownEpochLastBlock(chainID uint8) (*big.Int, error) {
-	key := new(bytes.Buffer)
-	err := binary.Write(key, binary.BigEndian, chainID)
-	if err != nil {
-		return nil, err
-	}
-	key.WriteString(latestKnownBlockKey)
-	data, err := db.db.Get(key.Bytes(), nil)
-	if err != nil {
-		if errors.Is(err, leveldb.ErrNotFound) {
-			return big.NewInt(0), nil
-		}
-		return nil, err
-	}
-	v := big.NewInt(0)
-	v.SetBytes(data)
-	return v, nil
+	return db.storage.LatestEpoch(chainID)
 }
 
-// Atomically sets block and validators as related KV to underlying DB backend
+// Atomically sets block, validators and their aggregated public key as related KV to underlying DB backend
 func (db *ValidatorsStore) SetValidatorsForBlock(block *big.Int, validators []*istanbul.ValidatorData, chainID uint8) error {
-	byteValidators := &bytes.Buffer{}
-	enc := gob.NewEncoder(byteValidators)
-	err := enc.Encode(validators)
-	if err != nil {
-		return err
+	// Validators without valid BLS keys are stored anyway, their APK is computed on request and fails there
+	apk, apkErr := aggregatePublicKeys(validators)
+	if apkErr != nil {
+		log.Debug().Err(apkErr).Str("block", block.String()).Msg("Unable to aggregate validators public keys")
+		apk = nil
 	}
-	tx, err := db.db.OpenTransaction()
+	err := db.storage.PutEpoch(chainID, block, validators, apk)
 	if err != nil {
 		return err
 	}
-	key := new(bytes.Buffer)
-	err = binary.Write(key, binary.BigEndian, chainID)
-	if err != nil {
-		return err
-	}
-	key.Write(block.Bytes())
-	err = tx.Put(key.Bytes(), byteValidators.Bytes(), nil)
-	if err != nil {
-		tx.Discard()
-		return err
-	}
-	err = db.setLatestKnownEpochLastBlockWithTransaction(block, chainID, tx)
-	if err != nil {
-		tx.Discard()
-		return err
-	}
-	err = tx.Commit()
-	if err != nil {
-		tx.Discard()
-		return err
+	epoch := epochKey{chainID: chainID, block: block.Uint64()}
+	if apkErr == nil {
+		db.apkCache.Add(epoch, apk)
+	} else {
+		db.apkCache.Remove(epoch)
 	}
+	db.notifyWaiters(epoch)
 	return nil
 }
 
 func (db *ValidatorsStore) GetValidatorsForBlock(block *big.Int, chainID uint8) ([]*istanbul.ValidatorData, error) {
-	key := new(bytes.Buffer)
-	err := binary.Write(key, binary.BigEndian, chainID)
-	if err != nil {
+	return db.storage.Validators(chainID, block)
+}
+
+var ErrNoBlockInStore = errors.New("no corresponding validators for provided block number")
+
+// GetAPKForBlock returns aggregated public key of validators for epoch of provided block.
+// Waits for validators sync to store the epoch until ctx is done
+func (db *ValidatorsStore) GetAPKForBlock(ctx context.Context, block *big.Int, chainID uint8, epochSize uint64) ([]byte, error) {
+	epochLastBlock := computeLastBlockOfEpochForProvidedBlock(block, epochSize)
+	key := epochKey{chainID: chainID, block: epochLastBlock.Uint64()}
+	if apk, ok := db.apkCache.Get(key); ok {
+		return apk.([]byte), nil
+	}
+	apk, err := db.storage.APK(chainID, epochLastBlock)
+	if err == nil {
+		db.apkCache.Add(key, apk)
+		return apk, nil
+	}
+	if !errors.Is(err, ErrEpochNotFound) {
 		return nil, err
 	}
-	key.Write(block.Bytes())
-	res, err := db.db.Get(key.Bytes(), nil)
+	vals, err := db.WaitForEpoch(ctx, epochLastBlock, chainID)
 	if err != nil {
 		return nil, err
 	}
-	b := &bytes.Buffer{}
-	b.Write(res)
-	dec := gob.NewDecoder(b)
-	dataArr := make([]*istanbul.ValidatorData, 0)
-	err = dec.Decode(&dataArr)
+	return db.repairAPK(epochLastBlock, chainID, vals)
+}
+
+// RepairAPKs stores aggregated public keys missing for stored epochs of chainID, eg. in databases created before
+// keys were stored with validators. Returns number of repaired epochs
+func (db *ValidatorsStore) RepairAPKs(chainID uint8, epochSize uint64) (int, error) {
+	latest, err := db.GetLatestKnownEpochLastBlock(chainID)
 	if err != nil {
-		return nil, err
+		return 0, err
+	}
+	repaired := 0
+	for block := big.NewInt(0); block.Cmp(latest) <= 0; block = new(big.Int).Add(block, new(big.Int).SetUint64(epochSize)) {
+		_, err := db.storage.APK(chainID, block)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, ErrEpochNotFound) {
+			return repaired, err
+		}
+		vals, err := db.GetValidatorsForBlock(block, chainID)
+		if errors.Is(err, ErrEpochNotFound) {
+			// Epochs before validators checkpoint are not stored
+			continue
+		}
+		if err != nil {
+			return repaired, err
+		}
+		_, err = db.repairAPK(block, chainID, vals)
+		if err != nil {
+			log.Warn().Err(err).Str("block", block.String()).Msg("Unable to repair aggregated public key")
+			continue
+		}
+		repaired++
 	}
-	return dataArr, nil
+	return repaired, nil
 }
 
-func (db *ValidatorsStore) setLatestKnownEpochLastBlockWithTransaction(block *big.Int, chainID uint8, transaction *leveldb.Transaction) error {
-	key := new(bytes.Buffer)
-	err := binary.Write(key, binary.BigEndian, chainID)
+// repairAPK computes and stores aggregated public key of epoch validators
+func (db *ValidatorsStore) repairAPK(epochLastBlock *big.Int, chainID uint8, validators []*istanbul.ValidatorData) ([]byte, error) {
+	apk, err := aggregatePublicKeys(validators)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	key.WriteString(latestKnownBlockKey)
-	err = transaction.Put(key.Bytes(), block.Bytes(), nil)
+	err = db.storage.PutAPK(chainID, epochLastBlock, apk)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	db.apkCache.Add(epochKey{chainID: chainID, block: epochLastBlock.Uint64()}, apk)
+	return apk, nil
 }
 
-var ErrNoBlockInStore = errors.New("no corresponding validators for provided block number")
+// WaitForEpoch returns validators stored for epoch last block. If epoch is not stored yet, validators sync is requested
+// to fetch it and call blocks until it is stored or ctx is done
+func (db *ValidatorsStore) WaitForEpoch(ctx context.Context, epochLastBlock *big.Int, chainID uint8) ([]*istanbul.ValidatorData, error) {
+	key := epochKey{chainID: chainID, block: epochLastBlock.Uint64()}
+	for {
+		// Waiter is registered before reading so commit between read and wait is not missed
+		db.lock.Lock()
+		stored := make(chan struct{})
+		db.waiters[key] = append(db.waiters[key], stored)
+		db.lock.Unlock()
 
-func (db *ValidatorsStore) GetAPKForBlock(block *big.Int, chainID uint8, epochSize uint64) ([]byte, error) {
-	for i := 0; i <= 10; i++ {
-		vals, err := db.GetValidatorsForBlock(computeLastBlockOfEpochForProvidedBlock(block, epochSize), chainID)
-		if err != nil {
-			if errors.Is(err, leveldb.ErrNotFound) {
-				time.Sleep(5 * time.Second)
-				continue
-			}
-			return nil, err
+		vals, err := db.GetValidatorsForBlock(epochLastBlock, chainID)
+		if err == nil {
+			db.removeWaiter(key, stored)
+			return vals, nil
 		}
-		pk, err := aggregatePublicKeys(vals)
-		if err != nil {
+		if !errors.Is(err, ErrEpochNotFound) {
+			db.removeWaiter(key, stored)
 			return nil, err
 		}
-		return pk.Serialize()
+		db.requestEpoch(chainID)
+		select {
+		case <-stored:
+			continue
+		case <-ctx.Done():
+			db.removeWaiter(key, stored)
+			return nil, fmt.Errorf("%w: epoch %s, %s", ErrNoBlockInStore, epochLastBlock.String(), ctx.Err())
+		}
+	}
+}
+
+// epochRequests returns channel signaled when validators of not yet stored epoch are awaited
+func (db *ValidatorsStore) epochRequests(chainID uint8) <-chan struct{} {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	return db.requestsChannel(chainID)
+}
+
+func (db *ValidatorsStore) requestEpoch(chainID uint8) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	select {
+	case db.requestsChannel(chainID) <- struct{}{}:
+	default:
+		// Request is already pending
+	}
+}
+
+// requestsChannel should be called under lock
+func (db *ValidatorsStore) requestsChannel(chainID uint8) chan struct{} {
+	ch, ok := db.requests[chainID]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		db.requests[chainID] = ch
+	}
+	return ch
+}
+
+func (db *ValidatorsStore) notifyWaiters(key epochKey) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	for _, ch := range db.waiters[key] {
+		close(ch)
+	}
+	delete(db.waiters, key)
+}
+
+func (db *ValidatorsStore) removeWaiter(key epochKey, stored chan struct{}) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	waiters := db.waiters[key]
+	for i, ch := range waiters {
+		if ch == stored {
+			db.waiters[key] = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(db.waiters[key]) == 0 {
+		delete(db.waiters, key)
 	}
-	return nil, ErrNoBlockInStore
 }
 
 // Closes connection to underlying DB backend
 func (db *ValidatorsStore) Close() error {
-	if err := db.db.Close(); err != nil {
-		return err
-	}
-	return nil
+	return db.storage.Close()
 }
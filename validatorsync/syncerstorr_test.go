@@ -3,10 +3,12 @@
 package validatorsync
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/istanbul"
@@ -18,7 +20,8 @@ import (
 
 type SyncerDBTestSuite struct {
 	suite.Suite
-	syncer *ValidatorsStore
+	syncer  *ValidatorsStore
+	storage *LevelDBStorage
 }
 
 func TestRunSyncerDBTestSuite(t *testing.T) {
@@ -31,8 +34,8 @@ func (s *SyncerDBTestSuite) SetupTest() {
 	if err != nil {
 		s.Fail(err.Error())
 	}
-	syncer := NewValidatorsStore(db)
-	s.syncer = syncer
+	s.storage = NewLevelDBStorage(db)
+	s.syncer = NewValidatorsStoreWithStorage(s.storage)
 }
 func (s *SyncerDBTestSuite) TearDownTest() {
 	s.syncer.Close()
@@ -132,7 +135,7 @@ func (s *SyncerDBTestSuite) TestGetAPKForBlock() {
 
 	err := s.syncer.SetValidatorsForBlock(big.NewInt(12), startVals, chainID)
 	s.Nil(err)
-	apk, err := s.syncer.GetAPKForBlock(big.NewInt(11), chainID, 12)
+	apk, err := s.syncer.GetAPKForBlock(context.Background(), big.NewInt(11), chainID, 12)
 	s.Nil(err)
 	s.NotNil(apk)
 }
@@ -145,8 +148,106 @@ func (s *SyncerDBTestSuite) TestGetAPKForBlockNotExistsBlockErr() {
 	startVals[2] = &istanbul.ValidatorData{Address: common.Address{0x2f}, BLSPublicKey: blscrypto.SerializedPublicKey{}}
 	err := s.syncer.SetValidatorsForBlock(big.NewInt(12), startVals, chainID)
 	s.Nil(err)
-	apk, err := s.syncer.GetAPKForBlock(big.NewInt(11), chainID, 13)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	apk, err := s.syncer.GetAPKForBlock(ctx, big.NewInt(11), chainID, 13)
 	s.NotNil(err)
 	s.True(errors.Is(err, ErrNoBlockInStore))
 	s.Nil(apk)
 }
+
+func (s *SyncerDBTestSuite) TestWaitForEpochReturnsWhenEpochIsStored() {
+	chainID := uint8(1)
+	validators, err := newTestValidators(1, 3)
+	s.Nil(err)
+	result := make(chan []*istanbul.ValidatorData)
+	go func() {
+		vals, err := s.syncer.WaitForEpoch(context.Background(), big.NewInt(24), chainID)
+		s.Nil(err)
+		result <- vals
+	}()
+
+	// Waiting request is signaled to validators sync
+	select {
+	case <-s.syncer.epochRequests(chainID):
+	case <-time.After(time.Second):
+		s.Fail("epoch was not requested")
+	}
+	// Other epochs and chains do not wake waiter up
+	s.Nil(s.syncer.SetValidatorsForBlock(big.NewInt(12), validatorsData(validators), chainID))
+	s.Nil(s.syncer.SetValidatorsForBlock(big.NewInt(24), validatorsData(validators), 2))
+	s.Nil(s.syncer.SetValidatorsForBlock(big.NewInt(24), validatorsData(validators[:2]), chainID))
+
+	select {
+	case vals := <-result:
+		s.Equal(validatorsData(validators[:2]), vals)
+	case <-time.After(time.Second):
+		s.Fail("waiter was not notified")
+	}
+	s.Equal(0, len(s.syncer.waiters))
+}
+
+func (s *SyncerDBTestSuite) TestWaitForEpochCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() {
+		_, err := s.syncer.WaitForEpoch(ctx, big.NewInt(24), 1)
+		errs <- err
+	}()
+	cancel()
+
+	select {
+	case err := <-errs:
+		s.True(errors.Is(err, ErrNoBlockInStore))
+	case <-time.After(time.Second):
+		s.Fail("waiter was not cancelled")
+	}
+	s.Equal(0, len(s.syncer.waiters))
+}
+
+func (s *SyncerDBTestSuite) TestSetValidatorsForBlockStoresAPK() {
+	chainID := uint8(1)
+	validators, err := newTestValidators(1, 3)
+	s.Nil(err)
+	err = s.syncer.SetValidatorsForBlock(big.NewInt(12), validatorsData(validators), chainID)
+	s.Nil(err)
+	expected, err := aggregatePublicKeys(validatorsData(validators))
+	s.Nil(err)
+
+	stored, err := s.storage.db.Get(apkKey(big.NewInt(12), chainID), nil)
+	s.Nil(err)
+	s.Equal(expected, stored)
+
+	// Served from stored key after cache is dropped
+	s.syncer.apkCache.Purge()
+	apk, err := s.syncer.GetAPKForBlock(context.Background(), big.NewInt(5), chainID, 12)
+	s.Nil(err)
+	s.Equal(expected, apk)
+	s.True(s.syncer.apkCache.Contains(epochKey{chainID: chainID, block: 12}))
+}
+
+func (s *SyncerDBTestSuite) TestRepairAPKs() {
+	chainID := uint8(1)
+	validators, err := newTestValidators(1, 4)
+	s.Nil(err)
+	s.Nil(s.syncer.SetValidatorsForBlock(big.NewInt(0), validatorsData(validators[:2]), chainID))
+	s.Nil(s.syncer.SetValidatorsForBlock(big.NewInt(12), validatorsData(validators[:3]), chainID))
+	s.Nil(s.syncer.SetValidatorsForBlock(big.NewInt(24), validatorsData(validators), chainID))
+	// Database created before aggregated public keys were stored
+	s.Nil(s.storage.db.Delete(apkKey(big.NewInt(0), chainID), nil))
+	s.Nil(s.storage.db.Delete(apkKey(big.NewInt(24), chainID), nil))
+	s.syncer.apkCache.Purge()
+
+	repaired, err := s.syncer.RepairAPKs(chainID, 12)
+	s.Nil(err)
+	s.Equal(2, repaired)
+	expected, err := aggregatePublicKeys(validatorsData(validators))
+	s.Nil(err)
+	stored, err := s.storage.db.Get(apkKey(big.NewInt(24), chainID), nil)
+	s.Nil(err)
+	s.Equal(expected, stored)
+
+	repaired, err = s.syncer.RepairAPKs(chainID, 12)
+	s.Nil(err)
+	s.Equal(0, repaired)
+}